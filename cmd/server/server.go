@@ -2,24 +2,184 @@ package main
 
 import (
 	"ascenda-loyalty-assignment/internal/handlers"
+	"ascenda-loyalty-assignment/internal/services/hotel_service"
+	"ascenda-loyalty-assignment/internal/services/update_jobs"
+	"ascenda-loyalty-assignment/internal/services/update_scheduler"
 	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-const port = ":8000"
+const (
+	port                    = ":8000"
+	mappingsDataDirName     = "mappings"
+	mergePolicyDataFileName = "merge_policy.json"
+	imageValidationFileName = "image_validation.json"
+	stalenessPolicyFileName = "staleness_policy.json"
+	updateScheduleFileName  = "update_schedule.json"
+	fetchPolicyFileName     = "fetch_policy.json"
+	validationRulesFileName = "validation_rules.json"
+	quarantineFileName      = "quarantine.json"
+	hotelMatchingFileName   = "hotel_matching.json"
+	hotelLinksFileName      = "hotel_links.json"
+	hotelsDataFileName      = "hotels.json"
+	hotelsDatabaseFileName  = "hotels.db"
+	suppliersDataFileName   = "suppliers.json"
+	updateLockFileName      = "update.lock"
+	snapshotsDataDirName    = "snapshots"
+	updateDiffsDataDirName  = "update_diffs"
+	supplierCacheDirName    = "supplier_cache"
+	maxSnapshots            = 50
+	supplierTimeout         = 60 * time.Second
+	updateJobQueueSize      = 10
+	maxUpdateJobs           = 100
+	// storageEnv selects the hotel storage backend: "json" (default) or "bolt".
+	storageEnv = "HOTEL_STORAGE"
+)
 
 func main() {
 	logger := logging.LogrusLogger()
 
+	wd, err := os.Getwd()
+	if err != nil {
+		logger.Critical("Error getting working directory", err)
+	}
+	mappings, err := hotel_service.LoadSupplierMappings(filepath.Join(wd, "internal", "data", mappingsDataDirName))
+	if err != nil {
+		logger.Critical("Failed to load supplier mappings", err)
+	}
+	mergePolicy, err := hotel_service.LoadMergePolicy(filepath.Join(wd, "internal", "data", mergePolicyDataFileName))
+	if err != nil {
+		logger.Critical("Failed to load merge policy", err)
+	}
+	imageValidation, err := hotel_service.LoadImageValidation(filepath.Join(wd, "internal", "data", imageValidationFileName))
+	if err != nil {
+		logger.Critical("Failed to load image validation", err)
+	}
+	stalenessPolicy, err := hotel_service.LoadStalenessPolicy(filepath.Join(wd, "internal", "data", stalenessPolicyFileName))
+	if err != nil {
+		logger.Critical("Failed to load staleness policy", err)
+	}
+	fetchPolicy, err := hotel_service.LoadFetchPolicy(filepath.Join(wd, "internal", "data", fetchPolicyFileName))
+	if err != nil {
+		logger.Critical("Failed to load fetch policy", err)
+	}
+	validationRules, err := hotel_service.LoadValidationRules(filepath.Join(wd, "internal", "data", validationRulesFileName))
+	if err != nil {
+		logger.Critical("Failed to load validation rules", err)
+	}
+	matchPolicy, err := hotel_service.LoadMatchPolicy(filepath.Join(wd, "internal", "data", hotelMatchingFileName))
+	if err != nil {
+		logger.Critical("Failed to load hotel matching policy", err)
+	}
+
+	var repository hotel_service.HotelRepository
+	switch storage := os.Getenv(storageEnv); storage {
+	case "", "json":
+		repository = hotel_service.NewJSONHotelRepository(logger, filepath.Join(wd, "internal", "data", hotelsDataFileName))
+	case "bolt":
+		boltRepository, err := hotel_service.NewBoltHotelRepository(filepath.Join(wd, "internal", "data", hotelsDatabaseFileName))
+		if err != nil {
+			logger.Critical("Failed to open hotel database", err)
+		}
+		defer boltRepository.Close()
+		repository = boltRepository
+	default:
+		logger.Critical(fmt.Sprintf("Unknown hotel storage %q", storage))
+	}
+
+	catalog, err := hotel_service.NewCatalog(repository)
+	if err != nil {
+		logger.Critical("Failed to load hotel catalog", err)
+	}
+
+	snapshots, err := hotel_service.NewSnapshotStore(filepath.Join(wd, "internal", "data", snapshotsDataDirName), maxSnapshots)
+	if err != nil {
+		logger.Critical("Failed to open hotel snapshots", err)
+	}
+
+	updateDiffs, err := hotel_service.NewUpdateDiffStore(filepath.Join(wd, "internal", "data", updateDiffsDataDirName))
+	if err != nil {
+		logger.Critical("Failed to open hotel update diffs", err)
+	}
+
+	payloadCache, err := hotel_service.NewPayloadCache(filepath.Join(wd, "internal", "data", supplierCacheDirName))
+	if err != nil {
+		logger.Critical("Failed to open supplier payload cache", err)
+	}
+
+	config := hotel_service.Config{
+		Repository:           repository,
+		Catalog:              catalog,
+		UpdateLock:           hotel_service.NewUpdateLock(filepath.Join(wd, "internal", "data", updateLockFileName)),
+		Snapshots:            snapshots,
+		UpdateDiffs:          updateDiffs,
+		SuppliersFilePath:    filepath.Join(wd, "internal", "data", suppliersDataFileName),
+		Mappings:             mappings,
+		Adapters:             hotel_service.DefaultSupplierAdapters(),
+		MergePolicy:          mergePolicy,
+		ImageValidation:      imageValidation,
+		StalenessPolicy:      stalenessPolicy,
+		RetryPolicy:          fetchPolicy.Retry,
+		PayloadCache:         payloadCache,
+		CircuitBreakers:      hotel_service.NewCircuitBreakers(fetchPolicy.CircuitBreaker),
+		PartialFailurePolicy: fetchPolicy.PartialFailure,
+		ValidationRules:      validationRules,
+		Quarantine:           hotel_service.NewQuarantineStore(filepath.Join(wd, "internal", "data", quarantineFileName)),
+		HotelLinks:           hotel_service.NewHotelLinkStore(filepath.Join(wd, "internal", "data", hotelLinksFileName)),
+		MatchPolicy:          matchPolicy,
+	}
+	if err := hotel_service.ValidateSuppliersFile(logger, config); err != nil {
+		logger.Critical("Invalid suppliers data file", err)
+	}
+
+	scheduleConfig, err := update_scheduler.LoadScheduleConfig(filepath.Join(wd, "internal", "data", updateScheduleFileName))
+	if err != nil {
+		logger.Critical("Failed to load update schedule", err)
+	}
+	runUpdate := func(ctx context.Context, options hotel_service.UpdateOptions) (hotel_service.UpdateResult, error) {
+		client := &http.Client{Timeout: supplierTimeout}
+		hotelService := hotel_service.NewHotelService(logger, client, ctx, config)
+		return hotelService.UpdateHotelsFromSuppliers(options)
+	}
+
+	jobs := update_jobs.NewManager(logger, runUpdate, updateJobQueueSize, maxUpdateJobs)
+	jobs.Start(context.Background())
+
+	var scheduler *update_scheduler.Scheduler
+	if scheduleConfig.Enabled {
+		scheduler, err = update_scheduler.NewScheduler(logger, scheduleConfig, func(ctx context.Context) error {
+			_, err := runUpdate(ctx, hotel_service.UpdateOptions{})
+			return err
+		})
+		if err != nil {
+			logger.Critical("Invalid update schedule", err)
+		}
+		scheduler.Start(context.Background())
+	}
+
 	router := gin.Default()
 
-	router.GET("/hotels", handlers.GetAllHotels(logger))
-	router.POST("/update_data", handlers.UpdateHotelData(logger))
+	router.GET("/hotels", handlers.GetAllHotels(logger, config))
+	router.POST("/update_data", handlers.UpdateHotelData(logger, jobs))
+	router.GET("/jobs/:id", handlers.GetJob(jobs))
+	router.GET("/snapshots", handlers.ListSnapshots(logger, config))
+	router.POST("/snapshots/:id/restore", handlers.RestoreSnapshot(logger, config))
+	router.GET("/updates/:id/diff", handlers.GetUpdateDiff(logger, config))
+	router.GET("/update_status", handlers.GetUpdateStatus(scheduler))
+	router.GET("/suppliers/circuits", handlers.GetSupplierCircuits(logger, config))
+	router.GET("/quarantine", handlers.GetQuarantine(logger, config))
+	router.GET("/hotel_links", handlers.ListHotelLinks(logger, config))
+	router.POST("/hotel_links/:id/confirm", handlers.ConfirmHotelLink(logger, config))
+	router.POST("/hotel_links/:id/reject", handlers.RejectHotelLink(logger, config))
 
-	err := http.ListenAndServe(port, router)
+	err = http.ListenAndServe(port, router)
 
 	if err != nil {
 		logger.Critical("HTTP server error: %v", err)
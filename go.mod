@@ -4,8 +4,10 @@ go 1.22.9
 
 require (
 	github.com/gin-gonic/gin v1.10.0
+	github.com/robfig/cron/v3 v3.0.1
 	github.com/sirupsen/logrus v1.9.3
 	github.com/stretchr/testify v1.9.0
+	go.etcd.io/bbolt v1.3.11
 )
 
 require (
@@ -2,27 +2,40 @@ package handlers
 
 import (
 	"ascenda-loyalty-assignment/internal/services/hotel_service"
+	"ascenda-loyalty-assignment/internal/services/update_jobs"
 	"ascenda-loyalty-assignment/pkg/logging"
+	"errors"
 	"fmt"
 	"github.com/gin-gonic/gin"
 	"net/http"
-	"os"
-	"path/filepath"
-	"time"
+	"strings"
 )
 
 const (
-	hotelsDataFileName      = "hotels.json"
-	suppliersDataFileName   = "suppliers.json"
-	defaultTimeOutInSeconds = 60
+	includeProvenance = "provenance"
+	includeInactive   = "inactive"
 )
 
 type HotelQueryParams struct {
 	HotelIDs       []string `form:"hotelIds"`
 	DestinationIDs []int    `form:"destinationIds"`
+	Countries      []string `form:"countries"`
+	Cities         []string `form:"cities"`
+	Include        []string `form:"include"`
 }
 
-func GetAllHotels(logger logging.Logger) gin.HandlerFunc {
+func (q HotelQueryParams) includes(field string) bool {
+	for _, include := range q.Include {
+		for _, value := range strings.Split(include, ",") {
+			if strings.TrimSpace(value) == field {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func GetAllHotels(logger logging.Logger, config hotel_service.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var queryParams HotelQueryParams
 		if err := c.ShouldBindQuery(&queryParams); err != nil {
@@ -30,42 +43,57 @@ func GetAllHotels(logger logging.Logger) gin.HandlerFunc {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
 			return
 		}
-		wd, err := os.Getwd()
+		hotelService := hotel_service.NewHotelService(logger, nil, c, config)
+		hotels, err := hotelService.GetHotels(queryParams.HotelIDs, queryParams.DestinationIDs, queryParams.Countries, queryParams.Cities)
 		if err != nil {
-			logger.Error("Error getting working directory", err)
 			c.Status(http.StatusInternalServerError)
 			return
 		}
-		hotelService := hotel_service.NewHotelService(logger, nil, c)
-		hotelDataFilePath := filepath.Join(wd, "internal", "data", hotelsDataFileName)
-		hotels, err := hotelService.GetHotels(hotelDataFilePath, queryParams.HotelIDs, queryParams.DestinationIDs)
-		if err != nil {
-			c.Status(http.StatusInternalServerError)
-			return
+		if !queryParams.includes(includeInactive) {
+			activeHotels := make([]hotel_service.Hotel, 0, len(hotels))
+			for _, hotel := range hotels {
+				if !hotel.Inactive {
+					activeHotels = append(activeHotels, hotel)
+				}
+			}
+			hotels = activeHotels
+		}
+		if !queryParams.includes(includeProvenance) {
+			for i := range hotels {
+				hotels[i].Provenance = nil
+			}
 		}
 		c.JSON(http.StatusOK, hotels)
 	}
 }
 
-func UpdateHotelData(logger logging.Logger) gin.HandlerFunc {
+type UpdateQueryParams struct {
+	DryRun bool `form:"dry_run"`
+}
+
+func UpdateHotelData(logger logging.Logger, jobs *update_jobs.Manager) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		wd, err := os.Getwd()
-		if err != nil {
-			logger.Error("Error getting working directory", err)
-			c.Status(http.StatusInternalServerError)
+		var queryParams UpdateQueryParams
+		if err := c.ShouldBindQuery(&queryParams); err != nil {
+			logger.Error(fmt.Sprintf("Invalid request params %v", c.Request.URL.Query()), err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request params"})
 			return
 		}
-		client := &http.Client{
-			Timeout: time.Second * defaultTimeOutInSeconds,
-		}
-		hotelService := hotel_service.NewHotelService(logger, client, c)
-		suppliersDataFilePath := filepath.Join(wd, "internal", "data", suppliersDataFileName)
-		hotelDataFilePath := filepath.Join(wd, "internal", "data", hotelsDataFileName)
-		fetchedSources, err := hotelService.UpdateHotelsFromSuppliers(suppliersDataFilePath, hotelDataFilePath)
+		job, err := jobs.Enqueue(hotel_service.UpdateOptions{DryRun: queryParams.DryRun})
 		if err != nil {
+			if errors.Is(err, update_jobs.ErrQueueFull) {
+				c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Too many pending updates, retry later"})
+				return
+			}
+			logger.Error("Failed to enqueue update job", err)
 			c.Status(http.StatusInternalServerError)
 			return
 		}
-		c.JSON(http.StatusOK, gin.H{"message": "Hotel data updated successfully", "sources": fetchedSources})
+		c.JSON(http.StatusAccepted, gin.H{
+			"message":    "Hotel data update queued",
+			"job_id":     job.ID,
+			"status":     job.Status,
+			"status_url": fmt.Sprintf("/jobs/%s", job.ID),
+		})
 	}
 }
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"ascenda-loyalty-assignment/internal/services/hotel_service"
+	"ascenda-loyalty-assignment/pkg/logging"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// ListHotelLinks lists the links between supplier hotel ids and canonical ids, optionally of a single status.
+func ListHotelLinks(logger logging.Logger, config hotel_service.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status := c.Query("status")
+		switch status {
+		case "", hotel_service.HotelLinkProposed, hotel_service.HotelLinkConfirmed, hotel_service.HotelLinkRejected:
+		default:
+			logger.Error(fmt.Sprintf("Invalid hotel link status %s", status))
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid hotel link status"})
+			return
+		}
+		hotelService := hotel_service.NewHotelService(logger, nil, c, config)
+		links, err := hotelService.ListHotelLinks(status)
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, links)
+	}
+}
+
+func ConfirmHotelLink(logger logging.Logger, config hotel_service.Config) gin.HandlerFunc {
+	return decideHotelLink(logger, config, hotel_service.HotelService.ConfirmHotelLink)
+}
+
+func RejectHotelLink(logger logging.Logger, config hotel_service.Config) gin.HandlerFunc {
+	return decideHotelLink(logger, config, hotel_service.HotelService.RejectHotelLink)
+}
+
+func decideHotelLink(logger logging.Logger, config hotel_service.Config, decide func(hotel_service.HotelService, string) (hotel_service.HotelLink, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hotelService := hotel_service.NewHotelService(logger, nil, c, config)
+		link, err := decide(hotelService, c.Param("id"))
+		if err != nil {
+			if errors.Is(err, hotel_service.ErrHotelLinkNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Hotel link not found"})
+				return
+			}
+			if errors.Is(err, hotel_service.ErrHotelLinkConflict) {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, link)
+	}
+}
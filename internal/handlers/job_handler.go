@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"ascenda-loyalty-assignment/internal/services/update_jobs"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func GetJob(jobs *update_jobs.Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		job, exists := jobs.Get(c.Param("id"))
+		if !exists {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+			return
+		}
+		c.JSON(http.StatusOK, job)
+	}
+}
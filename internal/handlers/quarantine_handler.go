@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"ascenda-loyalty-assignment/internal/services/hotel_service"
+	"ascenda-loyalty-assignment/pkg/logging"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// GetQuarantine lists the supplier records rejected by the validation, optionally of a single supplier.
+func GetQuarantine(logger logging.Logger, config hotel_service.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hotelService := hotel_service.NewHotelService(logger, nil, c, config)
+		records, err := hotelService.GetQuarantine(c.Query("supplier"))
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, records)
+	}
+}
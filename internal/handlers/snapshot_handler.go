@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"ascenda-loyalty-assignment/internal/services/hotel_service"
+	"ascenda-loyalty-assignment/pkg/logging"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+)
+
+func ListSnapshots(logger logging.Logger, config hotel_service.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hotelService := hotel_service.NewHotelService(logger, nil, c, config)
+		snapshots, err := hotelService.ListSnapshots()
+		if err != nil {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, snapshots)
+	}
+}
+
+func RestoreSnapshot(logger logging.Logger, config hotel_service.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			logger.Error(fmt.Sprintf("Invalid snapshot id %s", c.Param("id")), err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid snapshot id"})
+			return
+		}
+		hotelService := hotel_service.NewHotelService(logger, nil, c, config)
+		snapshot, err := hotelService.RestoreSnapshot(id)
+		if err != nil {
+			if errors.Is(err, hotel_service.ErrSnapshotNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Snapshot not found"})
+				return
+			}
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": fmt.Sprintf("Snapshot %d restored", id), "snapshot": snapshot})
+	}
+}
+
+func GetUpdateDiff(logger logging.Logger, config hotel_service.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil || id <= 0 {
+			logger.Error(fmt.Sprintf("Invalid update id %s", c.Param("id")), err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid update id"})
+			return
+		}
+		hotelService := hotel_service.NewHotelService(logger, nil, c, config)
+		diff, err := hotelService.GetUpdateDiff(id)
+		if err != nil {
+			if errors.Is(err, hotel_service.ErrUpdateNotFound) {
+				c.JSON(http.StatusNotFound, gin.H{"error": "Update not found"})
+				return
+			}
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"summary": diff.Summary(), "diff": diff})
+	}
+}
@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"ascenda-loyalty-assignment/internal/services/hotel_service"
+	"ascenda-loyalty-assignment/pkg/logging"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+func GetSupplierCircuits(logger logging.Logger, config hotel_service.Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		hotelService := hotel_service.NewHotelService(logger, nil, c, config)
+		c.JSON(http.StatusOK, hotelService.GetSupplierCircuits())
+	}
+}
@@ -0,0 +1,18 @@
+package handlers
+
+import (
+	"ascenda-loyalty-assignment/internal/services/update_scheduler"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// GetUpdateStatus reports the scheduled supplier refresh, scheduler is nil when the schedule is disabled.
+func GetUpdateStatus(scheduler *update_scheduler.Scheduler) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if scheduler == nil {
+			c.JSON(http.StatusOK, update_scheduler.Status{})
+			return
+		}
+		c.JSON(http.StatusOK, scheduler.Status())
+	}
+}
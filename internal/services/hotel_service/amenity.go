@@ -0,0 +1,137 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/utils"
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+const (
+	amenityCategoryGeneral = "general"
+	amenityCategoryRoom    = "room"
+)
+
+//go:embed reference_data/amenities.json
+var amenityVocabularyData []byte
+
+type amenityVocabularyEntry struct {
+	Name     string   `json:"name"`
+	Category string   `json:"category"`
+	Synonyms []string `json:"synonyms"`
+}
+
+// amenityVocabulary indexes every canonical amenity by the compact form (no spaces) of its name and synonyms.
+var amenityVocabulary = loadAmenityVocabulary(amenityVocabularyData)
+
+func loadAmenityVocabulary(data []byte) map[string]amenityVocabularyEntry {
+	var entries []amenityVocabularyEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		panic("invalid amenity vocabulary: " + err.Error())
+	}
+	vocabulary := make(map[string]amenityVocabularyEntry)
+	for _, entry := range entries {
+		vocabulary[compactAmenityKey(entry.Name)] = entry
+		for _, synonym := range entry.Synonyms {
+			vocabulary[compactAmenityKey(normalizeAmenityText(synonym))] = entry
+		}
+	}
+	return vocabulary
+}
+
+// canonicalAmenity maps a supplier amenity to its canonical name and category.
+// Amenities missing from the vocabulary keep their normalized text and the category given by the supplier.
+func canonicalAmenity(raw string, category string) (string, string) {
+	normalized := normalizeAmenityText(raw)
+	if normalized == "" {
+		return "", category
+	}
+	if entry, ok := amenityVocabulary[compactAmenityKey(normalized)]; ok {
+		return entry.Name, entry.Category
+	}
+	return normalized, category
+}
+
+// normalizeAmenityText splits camel case words, folds case and collapses separators and whitespace,
+// e.g. "BusinessCenter" -> "business center", " dry_cleaning " -> "dry cleaning".
+func normalizeAmenityText(raw string) string {
+	runes := []rune(strings.TrimSpace(raw))
+	var builder strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			previous := runes[i-1]
+			nextIsLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(previous) || (unicode.IsUpper(previous) && nextIsLower) {
+				builder.WriteRune(' ')
+			}
+		}
+		if r == '_' || r == '-' {
+			r = ' '
+		}
+		builder.WriteRune(unicode.ToLower(r))
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+func compactAmenityKey(normalized string) string {
+	return strings.ReplaceAll(normalized, " ", "")
+}
+
+// normalizeAmenities canonicalizes and deduplicates amenities, moving each one to its canonical category.
+func normalizeAmenities(amenities Amenities) Amenities {
+	var normalized Amenities
+	add := func(raw string, category string) {
+		name, category := canonicalAmenity(raw, category)
+		if name == "" {
+			return
+		}
+		if category == amenityCategoryRoom {
+			if !utils.SliceContains(normalized.Room, name) {
+				normalized.Room = append(normalized.Room, name)
+			}
+			return
+		}
+		if !utils.SliceContains(normalized.General, name) {
+			normalized.General = append(normalized.General, name)
+		}
+	}
+	for _, amenity := range amenities.General {
+		add(amenity, amenityCategoryGeneral)
+	}
+	for _, amenity := range amenities.Room {
+		add(amenity, amenityCategoryRoom)
+	}
+	return normalized
+}
+
+// normalizeStoredAmenities canonicalizes the amenities of a stored hotel and re-keys their provenance.
+func normalizeStoredAmenities(hotel *Hotel) {
+	hotel.Amenities = normalizeAmenities(hotel.Amenities)
+	if hotel.Provenance == nil {
+		return
+	}
+
+	elements := make(map[string]map[string]FieldSource)
+	for _, field := range []string{ProvenanceFieldGeneralAmenities, ProvenanceFieldRoomAmenities} {
+		category := amenityCategoryGeneral
+		if field == ProvenanceFieldRoomAmenities {
+			category = amenityCategoryRoom
+		}
+		for raw, source := range hotel.Provenance.Elements[field] {
+			name, category := canonicalAmenity(raw, category)
+			canonicalField := ProvenanceFieldGeneralAmenities
+			if category == amenityCategoryRoom {
+				canonicalField = ProvenanceFieldRoomAmenities
+			}
+			if elements[canonicalField] == nil {
+				elements[canonicalField] = make(map[string]FieldSource)
+			}
+			elements[canonicalField][name] = source
+		}
+		delete(hotel.Provenance.Elements, field)
+	}
+	for field, sources := range elements {
+		hotel.Provenance.setElements(field, sources)
+	}
+}
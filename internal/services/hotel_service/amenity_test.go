@@ -0,0 +1,61 @@
+package hotel_service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestNormalizeAmenities(t *testing.T) {
+	testCases := []struct {
+		description       string
+		amenities         Amenities
+		expectedAmenities Amenities
+	}{
+		{
+			description: "split camel case, fold case and map synonyms",
+			amenities: Amenities{
+				General: []string{"WiFi ", "BusinessCenter", " dry_cleaning", "Swimming Pool", "Rooftop Garden"},
+			},
+			expectedAmenities: Amenities{
+				General: []string{"wifi", "business center", "dry cleaning", "pool", "rooftop garden"},
+			},
+		},
+		{
+			description: "deduplicate on canonical values",
+			amenities: Amenities{
+				General: []string{"WiFi", "wifi", "Wi-Fi", "BusinessCenter", "business center"},
+				Room:    []string{"BathTub", "bathtub", "Tub"},
+			},
+			expectedAmenities: Amenities{
+				General: []string{"wifi", "business center"},
+				Room:    []string{"bathtub"},
+			},
+		},
+		{
+			description: "keep related amenities apart",
+			amenities: Amenities{
+				General: []string{"Laundry", "DryCleaning", "Internet", "WiFi", "Shuttle", "Airport Shuttle"},
+			},
+			expectedAmenities: Amenities{
+				General: []string{"laundry", "dry cleaning", "internet", "wifi", "shuttle", "airport shuttle"},
+			},
+		},
+		{
+			description: "reclassify room amenities reported as general",
+			amenities: Amenities{
+				General: []string{"Aircon", "BathTub", "Tv", "Pool"},
+				Room:    []string{"hair dryer", "Concierge"},
+			},
+			expectedAmenities: Amenities{
+				General: []string{"pool", "concierge"},
+				Room:    []string{"aircon", "bathtub", "tv", "hair dryer"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expectedAmenities, normalizeAmenities(tc.amenities))
+		})
+	}
+}
@@ -0,0 +1,190 @@
+package hotel_service
+
+import (
+	"encoding/json"
+	"fmt"
+	bolt "go.etcd.io/bbolt"
+	"time"
+)
+
+var hotelsBucket = []byte("hotels")
+
+// BoltHotelRepository stores each hotel as a JSON value of an embedded BoltDB file, keyed by hotel id.
+type BoltHotelRepository struct {
+	db *bolt.DB
+}
+
+// NewBoltHotelRepository opens or creates the database file, it must be closed with Close.
+func NewBoltHotelRepository(filePath string) (*BoltHotelRepository, error) {
+	db, err := bolt.Open(filePath, 0644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("error opening hotel database %s: %w", filePath, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(hotelsBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("error creating hotels bucket in %s: %w", filePath, err)
+	}
+	return &BoltHotelRepository{db: db}, nil
+}
+
+func (r *BoltHotelRepository) Close() error {
+	return r.db.Close()
+}
+
+func (r *BoltHotelRepository) Get(id string) (Hotel, bool, error) {
+	var hotel Hotel
+	found := false
+	err := r.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(hotelsBucket).Get([]byte(id))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &hotel)
+	})
+	if err != nil {
+		return Hotel{}, false, fmt.Errorf("error reading hotel %s: %w", id, err)
+	}
+	return hotel, found, nil
+}
+
+func (r *BoltHotelRepository) GetMany(ids []string) ([]Hotel, error) {
+	hotels := make([]Hotel, 0, len(ids))
+	err := r.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(hotelsBucket)
+		for _, id := range ids {
+			data := bucket.Get([]byte(id))
+			if data == nil {
+				continue
+			}
+			var hotel Hotel
+			if err := json.Unmarshal(data, &hotel); err != nil {
+				return err
+			}
+			hotels = append(hotels, hotel)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading hotels: %w", err)
+	}
+	return hotels, nil
+}
+
+func (r *BoltHotelRepository) FindByDestination(destinationID int) ([]Hotel, error) {
+	hotels := make([]Hotel, 0)
+	err := r.forEach(func(hotel Hotel) {
+		if hotel.DestinationID == destinationID {
+			hotels = append(hotels, hotel)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hotels, nil
+}
+
+func (r *BoltHotelRepository) Upsert(hotels ...Hotel) error {
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(hotelsBucket)
+		for _, hotel := range hotels {
+			data, err := json.Marshal(hotel)
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put([]byte(hotel.ID), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error writing hotels: %w", err)
+	}
+	return nil
+}
+
+func (r *BoltHotelRepository) Delete(ids ...string) error {
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(hotelsBucket)
+		for _, id := range ids {
+			if err := bucket.Delete([]byte(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error deleting hotels: %w", err)
+	}
+	return nil
+}
+
+// Replace deletes the hotels missing from the given ones and writes the others in a single transaction.
+func (r *BoltHotelRepository) Replace(hotels map[string]Hotel) error {
+	err := r.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(hotelsBucket)
+		var removedIds [][]byte
+		err := bucket.ForEach(func(id, _ []byte) error {
+			if _, exists := hotels[string(id)]; !exists {
+				removedIds = append(removedIds, append([]byte(nil), id...))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		for _, id := range removedIds {
+			if err := bucket.Delete(id); err != nil {
+				return err
+			}
+		}
+		for _, id := range sortedHotelIds(hotels) {
+			data, err := json.Marshal(hotels[id])
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put([]byte(id), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("error replacing hotels: %w", err)
+	}
+	return nil
+}
+
+func (r *BoltHotelRepository) Snapshot() (map[string]Hotel, error) {
+	hotels := make(map[string]Hotel)
+	err := r.forEach(func(hotel Hotel) {
+		hotels[hotel.ID] = hotel
+	})
+	if err != nil {
+		return nil, err
+	}
+	return hotels, nil
+}
+
+// forEach calls fn for every hotel in id order.
+func (r *BoltHotelRepository) forEach(fn func(hotel Hotel)) error {
+	err := r.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(hotelsBucket).ForEach(func(_, data []byte) error {
+			var hotel Hotel
+			if err := json.Unmarshal(data, &hotel); err != nil {
+				return err
+			}
+			fn(hotel)
+			return nil
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("error reading hotels: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package hotel_service
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// acmeAdapter handles the Acme payload: PascalCase keys, flat location fields and
+// latitude/longitude sent as null or empty strings when unknown.
+type acmeAdapter struct {
+	httpFetcher
+}
+
+type acmeHotel struct {
+	ID            flexString     `json:"Id"`
+	DestinationID flexInt        `json:"DestinationId"`
+	Name          flexString     `json:"Name"`
+	Latitude      flexFloat      `json:"Latitude"`
+	Longitude     flexFloat      `json:"Longitude"`
+	Address       flexString     `json:"Address"`
+	City          flexString     `json:"City"`
+	Country       flexString     `json:"Country"`
+	PostalCode    flexString     `json:"PostalCode"`
+	Description   flexString     `json:"Description"`
+	Facilities    flexStringList `json:"Facilities"`
+}
+
+func (a *acmeAdapter) Normalize(record json.RawMessage) (Hotel, error) {
+	var hotel acmeHotel
+	err := json.Unmarshal(record, &hotel)
+	if err != nil {
+		return Hotel{}, fmt.Errorf("invalid acme hotel data: %w", err)
+	}
+	err = validateHotelIdentity(string(hotel.ID), int(hotel.DestinationID))
+	if err != nil {
+		return Hotel{}, err
+	}
+
+	return Hotel{
+		ID:            string(hotel.ID),
+		DestinationID: int(hotel.DestinationID),
+		HotelName:     string(hotel.Name),
+		Location: Location{
+			Lat:     float64(hotel.Latitude),
+			Long:    float64(hotel.Longitude),
+			Address: string(hotel.Address),
+			City:    string(hotel.City),
+			Country: string(hotel.Country),
+		},
+		Description: optionalStringList(string(hotel.Description)),
+		Amenities: Amenities{
+			General: hotel.Facilities,
+		},
+	}, nil
+}
+
+// patagoniaAdapter handles the Patagonia payload: a flat amenity list and images with url/description.
+type patagoniaAdapter struct {
+	httpFetcher
+}
+
+type patagoniaHotel struct {
+	ID          flexString     `json:"id"`
+	Destination flexInt        `json:"destination"`
+	Name        flexString     `json:"name"`
+	Lat         flexFloat      `json:"lat"`
+	Lng         flexFloat      `json:"lng"`
+	Address     flexString     `json:"address"`
+	Info        flexString     `json:"info"`
+	Amenities   flexStringList `json:"amenities"`
+	Images      interface{}    `json:"images"`
+}
+
+func (a *patagoniaAdapter) Normalize(record json.RawMessage) (Hotel, error) {
+	var hotel patagoniaHotel
+	err := json.Unmarshal(record, &hotel)
+	if err != nil {
+		return Hotel{}, fmt.Errorf("invalid patagonia hotel data: %w", err)
+	}
+	err = validateHotelIdentity(string(hotel.ID), int(hotel.Destination))
+	if err != nil {
+		return Hotel{}, err
+	}
+
+	images, _ := coerceImages(hotel.Images, []string{"url"}, []string{"description"})
+	return Hotel{
+		ID:            string(hotel.ID),
+		DestinationID: int(hotel.Destination),
+		HotelName:     string(hotel.Name),
+		Location: Location{
+			Lat:     float64(hotel.Lat),
+			Long:    float64(hotel.Lng),
+			Address: string(hotel.Address),
+		},
+		Description: optionalStringList(string(hotel.Info)),
+		Amenities: Amenities{
+			General: hotel.Amenities,
+		},
+		Images: images,
+	}, nil
+}
+
+// paperfliesAdapter handles the Paperflies payload: nested location, general/room amenities,
+// images with link/caption and booking conditions.
+type paperfliesAdapter struct {
+	httpFetcher
+}
+
+// paperfliesLocation is an object with address and country, a plain string is the address.
+type paperfliesLocation struct {
+	Address flexString `json:"address"`
+	Country flexString `json:"country"`
+}
+
+func (l *paperfliesLocation) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if object, ok := value.(map[string]interface{}); ok {
+		address, _ := coerceString(object["address"])
+		country, _ := coerceString(object["country"])
+		*l = paperfliesLocation{Address: flexString(address), Country: flexString(country)}
+		return nil
+	}
+	address, _ := coerceString(value)
+	*l = paperfliesLocation{Address: flexString(address)}
+	return nil
+}
+
+// paperfliesAmenities is an object with general and room amenities, a flat list holds general amenities.
+type paperfliesAmenities struct {
+	General flexStringList `json:"general"`
+	Room    flexStringList `json:"room"`
+}
+
+func (a *paperfliesAmenities) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	if object, ok := value.(map[string]interface{}); ok {
+		general, _ := coerceStringList(object["general"])
+		room, _ := coerceStringList(object["room"])
+		*a = paperfliesAmenities{General: general, Room: room}
+		return nil
+	}
+	general, _ := coerceStringList(value)
+	*a = paperfliesAmenities{General: general}
+	return nil
+}
+
+type paperfliesHotel struct {
+	HotelID           flexString          `json:"hotel_id"`
+	DestinationID     flexInt             `json:"destination_id"`
+	HotelName         flexString          `json:"hotel_name"`
+	Location          paperfliesLocation  `json:"location"`
+	Details           flexString          `json:"details"`
+	Amenities         paperfliesAmenities `json:"amenities"`
+	Images            interface{}         `json:"images"`
+	BookingConditions flexStringList      `json:"booking_conditions"`
+}
+
+func (a *paperfliesAdapter) Normalize(record json.RawMessage) (Hotel, error) {
+	var hotel paperfliesHotel
+	err := json.Unmarshal(record, &hotel)
+	if err != nil {
+		return Hotel{}, fmt.Errorf("invalid paperflies hotel data: %w", err)
+	}
+	err = validateHotelIdentity(string(hotel.HotelID), int(hotel.DestinationID))
+	if err != nil {
+		return Hotel{}, err
+	}
+
+	images, _ := coerceImages(hotel.Images, []string{"link"}, []string{"caption"})
+	return Hotel{
+		ID:            string(hotel.HotelID),
+		DestinationID: int(hotel.DestinationID),
+		HotelName:     string(hotel.HotelName),
+		Location: Location{
+			Address: string(hotel.Location.Address),
+			Country: string(hotel.Location.Country),
+		},
+		Description: optionalStringList(string(hotel.Details)),
+		Amenities: Amenities{
+			General: hotel.Amenities.General,
+			Room:    hotel.Amenities.Room,
+		},
+		Images:           images,
+		BookingCondition: hotel.BookingConditions,
+	}, nil
+}
+
+func validateHotelIdentity(id string, destinationId int) error {
+	if strings.TrimSpace(id) == "" {
+		return fmt.Errorf("data is invalid, missing hotelId")
+	}
+	if destinationId == 0 {
+		return fmt.Errorf("data is invalid, missing or invalid destination id for hotel %s", id)
+	}
+	return nil
+}
+
+func optionalStringList(value string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return []string{value}
+}
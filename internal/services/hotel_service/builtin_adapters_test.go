@@ -0,0 +1,164 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBuiltinAdaptersNormalize(t *testing.T) {
+	wd, _ := os.Getwd()
+	mappings, err := LoadSupplierMappings(filepath.Join(wd, "..", "..", "data", "mappings"))
+	assert.Nil(t, err)
+
+	testCases := []struct {
+		description   string
+		adapter       SupplierAdapter
+		mapping       string
+		record        string
+		expectedErr   bool
+		expectedHotel Hotel
+	}{
+		{
+			description: "acme trims values and ignores empty coordinates",
+			adapter:     &acmeAdapter{},
+			mapping:     "acme",
+			record: `{
+				"Id": "iJhz", "DestinationId": 5432, "Name": "Beach Villas Singapore",
+				"Latitude": "", "Longitude": null,
+				"Address": " 8 Sentosa Gateway, Beach Villas ", "City": "Singapore", "Country": "SG",
+				"PostalCode": "098269", "Description": "  This 5 star hotel is located on the coastline of Singapore.",
+				"Facilities": ["Pool", "WiFi ", " Breakfast"]
+			}`,
+			expectedHotel: Hotel{
+				ID:            "iJhz",
+				DestinationID: 5432,
+				HotelName:     "Beach Villas Singapore",
+				Location:      Location{Address: "8 Sentosa Gateway, Beach Villas", City: "Singapore", Country: "SG"},
+				Description:   []string{"This 5 star hotel is located on the coastline of Singapore."},
+				Amenities:     Amenities{General: []string{"Pool", "WiFi", "Breakfast"}},
+			},
+		},
+		{
+			description: "acme reads numbers sent as strings and ids sent as numbers",
+			adapter:     &acmeAdapter{},
+			mapping:     "acme",
+			record: `{
+				"Id": 1234, "DestinationId": "5432", "Name": "Beach Villas Singapore",
+				"Latitude": " 1.264751 ", "Longitude": "103.824006", "PostalCode": 98269,
+				"Facilities": "Pool"
+			}`,
+			expectedHotel: Hotel{
+				ID:            "1234",
+				DestinationID: 5432,
+				HotelName:     "Beach Villas Singapore",
+				Location:      Location{Lat: 1.264751, Long: 103.824006},
+				Amenities:     Amenities{General: []string{"Pool"}},
+			},
+		},
+		{
+			description: "acme rejects fractional destination",
+			adapter:     &acmeAdapter{},
+			mapping:     "acme",
+			record:      `{"Id": "iJhz", "DestinationId": "5432.5", "Name": "Beach Villas Singapore"}`,
+			expectedErr: true,
+		},
+		{
+			description: "acme rejects hotel without destination",
+			adapter:     &acmeAdapter{},
+			mapping:     "acme",
+			record:      `{"Id": "iJhz", "Name": "Beach Villas Singapore"}`,
+			expectedErr: true,
+		},
+		{
+			description: "patagonia maps info, flat amenities and url images",
+			adapter:     &patagoniaAdapter{},
+			mapping:     "patagonia",
+			record: `{
+				"id": "f8c9", "destination": 1122, "name": "Hilton Tokyo Shinjuku",
+				"lat": 35.6926, "lng": 139.690965, "address": null, "info": null,
+				"amenities": ["Aircon", "Tv"],
+				"images": {"rooms": [{"url": "https://d2ey9sqrvkqdfs.cloudfront.net/YwAr/i10_m.jpg", "description": "Suite"}]}
+			}`,
+			expectedHotel: Hotel{
+				ID:            "f8c9",
+				DestinationID: 1122,
+				HotelName:     "Hilton Tokyo Shinjuku",
+				Location:      Location{Lat: 35.6926, Long: 139.690965},
+				Amenities:     Amenities{General: []string{"Aircon", "Tv"}},
+				Images: map[string][]Image{
+					"rooms": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/YwAr/i10_m.jpg", Description: "Suite"}},
+				},
+			},
+		},
+		{
+			description: "paperflies maps nested location, room amenities and booking conditions",
+			adapter:     &paperfliesAdapter{},
+			mapping:     "paperflies",
+			record: `{
+				"hotel_id": "SjyX", "destination_id": 5432, "hotel_name": "InterContinental",
+				"location": {"address": "1 Nanson Rd, Singapore 238909", "country": "Singapore"},
+				"details": "InterContinental Singapore Robertson Quay.",
+				"amenities": {"general": ["outdoor pool"], "room": ["aircon", "minibar"]},
+				"images": {"site": [{"link": "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i1_m.jpg", "caption": "Restaurant"}]},
+				"booking_conditions": ["Pets are not allowed."]
+			}`,
+			expectedHotel: Hotel{
+				ID:            "SjyX",
+				DestinationID: 5432,
+				HotelName:     "InterContinental",
+				Location:      Location{Address: "1 Nanson Rd, Singapore 238909", Country: "Singapore"},
+				Description:   []string{"InterContinental Singapore Robertson Quay."},
+				Amenities:     Amenities{General: []string{"outdoor pool"}, Room: []string{"aircon", "minibar"}},
+				Images: map[string][]Image{
+					"site": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i1_m.jpg", Description: "Restaurant"}},
+				},
+				BookingCondition: []string{"Pets are not allowed."},
+			},
+		},
+		{
+			description: "paperflies reads a flat location and amenity list",
+			adapter:     &paperfliesAdapter{},
+			mapping:     "paperflies",
+			record: `{
+				"hotel_id": "SjyX", "destination_id": 5432, "hotel_name": null,
+				"location": "1 Nanson Rd, Singapore 238909",
+				"amenities": ["outdoor pool", null, ""],
+				"images": "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i1_m.jpg",
+				"booking_conditions": null
+			}`,
+			expectedHotel: Hotel{
+				ID:            "SjyX",
+				DestinationID: 5432,
+				Location:      Location{Address: "1 Nanson Rd, Singapore 238909"},
+				Amenities:     Amenities{General: []string{"outdoor pool"}},
+			},
+		},
+		{
+			description: "paperflies rejects record that is not an object",
+			adapter:     &paperfliesAdapter{},
+			mapping:     "paperflies",
+			record:      `["SjyX"]`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			// the bundled supplier mapping reads the record the same way as the built-in adapter
+			mappingAdapter := newMappingAdapter(mappings[tc.mapping], logging.LogrusLogger())
+			for _, adapter := range []SupplierAdapter{tc.adapter, mappingAdapter} {
+				hotel, err := adapter.Normalize(json.RawMessage(tc.record))
+				if tc.expectedErr {
+					assert.NotNil(t, err)
+				} else {
+					assert.Nil(t, err)
+					assert.Equal(t, tc.expectedHotel, hotel)
+				}
+			}
+		})
+	}
+}
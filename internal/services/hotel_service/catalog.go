@@ -0,0 +1,106 @@
+package hotel_service
+
+import (
+	"sync/atomic"
+)
+
+// Catalog serves hotel reads from memory. The hotels and their secondary indexes are rebuilt as a whole
+// and swapped atomically, so readers always see a consistent set of hotels while an update is running.
+type Catalog struct {
+	repository HotelRepository
+	index      atomic.Pointer[catalogIndex]
+}
+
+// catalogIndex is immutable once built, id lists are sorted.
+type catalogIndex struct {
+	hotels        map[string]Hotel
+	byDestination map[int][]string
+	byCountry     map[string][]string
+	byCity        map[string][]string
+}
+
+// NewCatalog loads every hotel of the repository once.
+func NewCatalog(repository HotelRepository) (*Catalog, error) {
+	catalog := &Catalog{repository: repository}
+	if err := catalog.Reload(); err != nil {
+		return nil, err
+	}
+	return catalog, nil
+}
+
+// Reload rebuilds the catalog from the repository.
+func (c *Catalog) Reload() error {
+	hotels, err := c.repository.Snapshot()
+	if err != nil {
+		return err
+	}
+	c.replace(hotels)
+	return nil
+}
+
+// replace swaps in the given hotels, the map must not be modified afterwards.
+func (c *Catalog) replace(hotels map[string]Hotel) {
+	c.index.Store(newCatalogIndex(hotels))
+}
+
+func newCatalogIndex(hotels map[string]Hotel) *catalogIndex {
+	index := &catalogIndex{
+		hotels:        hotels,
+		byDestination: make(map[int][]string),
+		byCountry:     make(map[string][]string),
+		byCity:        make(map[string][]string),
+	}
+	for _, id := range sortedHotelIds(hotels) {
+		hotel := hotels[id]
+		index.byDestination[hotel.DestinationID] = append(index.byDestination[hotel.DestinationID], id)
+		if country := normalizeCountryCode(hotel.Location.Country); country != "" {
+			index.byCountry[country] = append(index.byCountry[country], id)
+		}
+		if city := locationKey(normalizeCity(hotel.Location.City)); city != "" {
+			index.byCity[city] = append(index.byCity[city], id)
+		}
+	}
+	return index
+}
+
+func (c *Catalog) Get(id string) (Hotel, bool) {
+	hotel, ok := c.index.Load().hotels[id]
+	return hotel, ok
+}
+
+// GetMany returns the hotels with the given ids, unknown ids are skipped.
+func (c *Catalog) GetMany(ids []string) []Hotel {
+	index := c.index.Load()
+	hotels := make([]Hotel, 0, len(ids))
+	for _, id := range ids {
+		if hotel, ok := index.hotels[id]; ok {
+			hotels = append(hotels, hotel)
+		}
+	}
+	return hotels
+}
+
+func (c *Catalog) FindByDestination(destinationID int) []Hotel {
+	index := c.index.Load()
+	return index.lookup(index.byDestination[destinationID])
+}
+
+// FindByCountry accepts an ISO code, a country name or a known alias.
+func (c *Catalog) FindByCountry(country string) []Hotel {
+	index := c.index.Load()
+	return index.lookup(index.byCountry[normalizeCountryCode(country)])
+}
+
+// FindByCity accepts a city name or a known alias, case and punctuation are ignored.
+func (c *Catalog) FindByCity(city string) []Hotel {
+	index := c.index.Load()
+	return index.lookup(index.byCity[locationKey(normalizeCity(city))])
+}
+
+func (i *catalogIndex) lookup(ids []string) []Hotel {
+	hotels := make([]Hotel, 0, len(ids))
+	for _, id := range ids {
+		hotels = append(hotels, i.hotels[id])
+	}
+	return hotels
+}
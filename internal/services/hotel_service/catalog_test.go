@@ -0,0 +1,92 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestCatalog(t *testing.T) {
+	repository := NewJSONHotelRepository(logging.LogrusLogger(), filepath.Join(t.TempDir(), "hotels.json"))
+	err := repository.Upsert(
+		Hotel{ID: "iJhz", DestinationID: 5432, Location: Location{City: "Singapore", Country: "SG"}},
+		Hotel{ID: "SjyX", DestinationID: 5432, Location: Location{City: "Singapore", Country: "SG"}},
+		Hotel{ID: "f8c9", DestinationID: 1122, Location: Location{City: "Tokyo", Country: "JP"}},
+	)
+	assert.Nil(t, err)
+	catalog, err := NewCatalog(repository)
+	assert.Nil(t, err)
+
+	testCases := []struct {
+		description string
+		find        func() []Hotel
+		expectedIds []string
+	}{
+		{
+			description: "get many skips unknown ids",
+			find:        func() []Hotel { return catalog.GetMany([]string{"f8c9", "unknown"}) },
+			expectedIds: []string{"f8c9"},
+		},
+		{
+			description: "find by destination",
+			find:        func() []Hotel { return catalog.FindByDestination(5432) },
+			expectedIds: []string{"SjyX", "iJhz"},
+		},
+		{
+			description: "find by country name",
+			find:        func() []Hotel { return catalog.FindByCountry("japan") },
+			expectedIds: []string{"f8c9"},
+		},
+		{
+			description: "find by city alias",
+			find:        func() []Hotel { return catalog.FindByCity("tokyo-to") },
+			expectedIds: []string{"f8c9"},
+		},
+		{
+			description: "find nothing for unknown city",
+			find:        func() []Hotel { return catalog.FindByCity("Paris") },
+			expectedIds: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			ids := make([]string, 0)
+			for _, hotel := range tc.find() {
+				ids = append(ids, hotel.ID)
+			}
+			assert.Equal(t, tc.expectedIds, ids)
+		})
+	}
+}
+
+func TestCatalogReplaceWhileReading(t *testing.T) {
+	catalog := &Catalog{}
+	catalog.replace(map[string]Hotel{"iJhz": {ID: "iJhz", DestinationID: 5432}})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				hotels := catalog.FindByDestination(5432)
+				// every index swapped in holds hotels of the destination
+				assert.NotEmpty(t, hotels)
+			}
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		catalog.replace(map[string]Hotel{
+			"iJhz": {ID: "iJhz", DestinationID: 5432},
+			"SjyX": {ID: "SjyX", DestinationID: 5432},
+		})
+	}
+	wg.Wait()
+
+	hotel, found := catalog.Get("SjyX")
+	assert.True(t, found)
+	assert.Equal(t, 5432, hotel.DestinationID)
+}
@@ -0,0 +1,129 @@
+package hotel_service
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// CircuitBreakerPolicy opens the circuit of a supplier after FailureThreshold updates in a row failed to fetch
+// it, the supplier is then skipped for OpenSeconds before a single trial fetch. FailureThreshold zero disables it.
+type CircuitBreakerPolicy struct {
+	FailureThreshold int `json:"failure_threshold"`
+	OpenSeconds      int `json:"open_seconds"`
+}
+
+// SupplierCircuit is the circuit breaker state of a supplier.
+type SupplierCircuit struct {
+	Supplier            string       `json:"supplier"`
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenUntil           *time.Time   `json:"open_until,omitempty"`
+}
+
+// CircuitBreakers holds the circuit of every supplier, services sharing suppliers must share it.
+type CircuitBreakers struct {
+	policy CircuitBreakerPolicy
+	now    func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*SupplierCircuit
+}
+
+func NewCircuitBreakers(policy CircuitBreakerPolicy) *CircuitBreakers {
+	return &CircuitBreakers{
+		policy:   policy,
+		now:      time.Now,
+		circuits: make(map[string]*SupplierCircuit),
+	}
+}
+
+func (b *CircuitBreakers) circuit(supplier string) *SupplierCircuit {
+	circuit, exists := b.circuits[supplier]
+	if !exists {
+		circuit = &SupplierCircuit{Supplier: supplier, State: CircuitClosed}
+		b.circuits[supplier] = circuit
+	}
+	return circuit
+}
+
+// allow tells whether the supplier may be fetched, an open circuit turns half open once its delay is over and
+// lets a single trial fetch through. A half open circuit has its trial in flight, the other callers are
+// rejected until the trial records a success or a failure.
+func (b *CircuitBreakers) allow(supplier string) bool {
+	if b == nil || b.policy.FailureThreshold <= 0 {
+		return true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	circuit := b.circuit(supplier)
+	switch circuit.State {
+	case CircuitClosed:
+		return true
+	case CircuitHalfOpen:
+		return false
+	}
+	if b.now().Before(*circuit.OpenUntil) {
+		return false
+	}
+	circuit.State = CircuitHalfOpen
+	circuit.OpenUntil = nil
+	return true
+}
+
+func (b *CircuitBreakers) recordSuccess(supplier string) {
+	if b == nil || b.policy.FailureThreshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	circuit := b.circuit(supplier)
+	circuit.State = CircuitClosed
+	circuit.ConsecutiveFailures = 0
+	circuit.OpenUntil = nil
+}
+
+func (b *CircuitBreakers) recordFailure(supplier string) {
+	if b == nil || b.policy.FailureThreshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	circuit := b.circuit(supplier)
+	circuit.ConsecutiveFailures++
+	if circuit.State == CircuitHalfOpen || circuit.ConsecutiveFailures >= b.policy.FailureThreshold {
+		openUntil := b.now().UTC().Add(time.Duration(b.policy.OpenSeconds) * time.Second)
+		circuit.State = CircuitOpen
+		circuit.OpenUntil = &openUntil
+	}
+}
+
+// States returns the circuit of every supplier fetched so far, sorted by supplier name.
+func (b *CircuitBreakers) States() []SupplierCircuit {
+	states := []SupplierCircuit{}
+	if b == nil {
+		return states
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, circuit := range b.circuits {
+		state := *circuit
+		if state.OpenUntil != nil {
+			openUntil := *state.OpenUntil
+			state.OpenUntil = &openUntil
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].Supplier < states[j].Supplier
+	})
+	return states
+}
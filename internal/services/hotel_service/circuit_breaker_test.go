@@ -0,0 +1,125 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakers(t *testing.T) {
+	now := time.Date(2024, 11, 1, 8, 0, 0, 0, time.UTC)
+	breakers := NewCircuitBreakers(CircuitBreakerPolicy{FailureThreshold: 2, OpenSeconds: 60})
+	breakers.now = func() time.Time { return now }
+
+	assert.True(t, breakers.allow("acme"))
+	breakers.recordFailure("acme")
+	assert.True(t, breakers.allow("acme"))
+	breakers.recordFailure("acme")
+	assert.False(t, breakers.allow("acme"), "the circuit opens after the failure threshold")
+
+	openUntil := now.Add(time.Minute)
+	assert.Equal(t, []SupplierCircuit{
+		{Supplier: "acme", State: CircuitOpen, ConsecutiveFailures: 2, OpenUntil: &openUntil},
+	}, breakers.States())
+
+	now = now.Add(time.Minute)
+	assert.True(t, breakers.allow("acme"), "a trial fetch is allowed once the delay is over")
+	assert.Equal(t, CircuitHalfOpen, breakers.States()[0].State)
+	assert.False(t, breakers.allow("acme"), "other callers are rejected while the trial is in flight")
+	breakers.recordFailure("acme")
+	assert.False(t, breakers.allow("acme"), "a failed trial opens the circuit again")
+
+	now = now.Add(time.Minute)
+	assert.True(t, breakers.allow("acme"))
+	breakers.recordSuccess("acme")
+	assert.Equal(t, []SupplierCircuit{
+		{Supplier: "acme", State: CircuitClosed},
+	}, breakers.States())
+}
+
+func TestCircuitBreakersSingleTrial(t *testing.T) {
+	now := time.Date(2024, 11, 1, 8, 0, 0, 0, time.UTC)
+	breakers := NewCircuitBreakers(CircuitBreakerPolicy{FailureThreshold: 1, OpenSeconds: 60})
+	breakers.now = func() time.Time { return now }
+	breakers.recordFailure("acme")
+	now = now.Add(time.Minute)
+
+	start := make(chan struct{})
+	allowed := make(chan bool, 2)
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			allowed <- breakers.allow("acme")
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(allowed)
+
+	trials := 0
+	for ok := range allowed {
+		if ok {
+			trials++
+		}
+	}
+	assert.Equal(t, 1, trials, "a single caller runs the trial fetch")
+
+	breakers.recordSuccess("acme")
+	assert.True(t, breakers.allow("acme"), "the circuit closes after a successful trial")
+	assert.True(t, breakers.allow("acme"))
+}
+
+func TestCircuitBreakersDisabled(t *testing.T) {
+	var nilBreakers *CircuitBreakers
+	assert.True(t, nilBreakers.allow("acme"))
+	nilBreakers.recordFailure("acme")
+	assert.Equal(t, []SupplierCircuit{}, nilBreakers.States())
+
+	breakers := NewCircuitBreakers(CircuitBreakerPolicy{})
+	for i := 0; i < 5; i++ {
+		breakers.recordFailure("acme")
+	}
+	assert.True(t, breakers.allow("acme"))
+}
+
+func TestUpdateHotelsFromSuppliersCircuitBreaker(t *testing.T) {
+	mockClient := &MockHTTPClient{
+		Errors: map[string]error{"https://example.com/hotels": fmt.Errorf("connection refused")},
+	}
+	logger := logging.LogrusLogger()
+	wd, _ := os.Getwd()
+	breakers := NewCircuitBreakers(CircuitBreakerPolicy{FailureThreshold: 1, OpenSeconds: 60})
+	config := Config{
+		Repository:        NewJSONHotelRepository(logger, copyTestDataFile(t, "test_sanitize_data.json")),
+		SuppliersFilePath: filepath.Join(wd, "test_data", "test_suppliers.json"),
+		CircuitBreakers:   breakers,
+	}
+	hotelService := NewHotelService(logger, mockClient, context.Background(), config)
+
+	_, err := hotelService.UpdateHotelsFromSuppliers(UpdateOptions{})
+	assert.Error(t, err)
+	assert.Equal(t, CircuitOpen, hotelService.GetSupplierCircuits()[0].State)
+
+	mockClient.Errors = nil
+	mockClient.Responses = map[string]*http.Response{"https://example.com/hotels": {StatusCode: http.StatusOK}}
+	var progress []SupplierProgress
+	_, err = hotelService.UpdateHotelsFromSuppliers(UpdateOptions{Progress: func(supplierProgress SupplierProgress) {
+		progress = append(progress, supplierProgress)
+	}})
+	assert.Error(t, err, "the open circuit skips the only supplier")
+	assert.Equal(t, []SupplierProgress{{
+		Supplier: "example",
+		Status:   SupplierStatusFailed,
+		Error:    "supplier example skipped, its circuit breaker is open",
+	}}, progress)
+}
@@ -0,0 +1,173 @@
+package hotel_service
+
+import (
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The coerce functions read the loosely typed values suppliers send: numbers sent as strings, strings sent as
+// numbers, empty strings and nulls for unknown values. They never fail, a value that cannot be read returns
+// false and the field is treated as missing.
+
+// coerceString reads a JSON scalar as a trimmed string, objects, lists, null and blank strings are missing.
+func coerceString(value interface{}) (string, bool) {
+	var str string
+	switch typed := value.(type) {
+	case string:
+		str = strings.TrimSpace(typed)
+	case float64:
+		str = strconv.FormatFloat(typed, 'f', -1, 64)
+	case json.Number:
+		str = typed.String()
+	case bool:
+		str = strconv.FormatBool(typed)
+	default:
+		return "", false
+	}
+	return str, str != ""
+}
+
+// coerceFloat reads a finite number, or a string holding one.
+func coerceFloat(value interface{}) (float64, bool) {
+	var number float64
+	switch typed := value.(type) {
+	case float64:
+		number = typed
+	case json.Number:
+		parsed, err := typed.Float64()
+		if err != nil {
+			return 0, false
+		}
+		number = parsed
+	case string:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(typed), 64)
+		if err != nil {
+			return 0, false
+		}
+		number = parsed
+	default:
+		return 0, false
+	}
+	if math.IsNaN(number) || math.IsInf(number, 0) {
+		return 0, false
+	}
+	return number, true
+}
+
+// coerceInt reads an integer, or a string holding one. Fractional numbers are not integers.
+func coerceInt(value interface{}) (int, bool) {
+	number, ok := coerceFloat(value)
+	if !ok || number != math.Trunc(number) || number > math.MaxInt32 || number < math.MinInt32 {
+		return 0, false
+	}
+	return int(number), true
+}
+
+// coerceStringList reads a list of scalars, a single scalar is a list of one. Missing elements are dropped.
+func coerceStringList(value interface{}) ([]string, bool) {
+	list, ok := value.([]interface{})
+	if !ok {
+		if str, ok := coerceString(value); ok {
+			return []string{str}, true
+		}
+		return nil, value == nil
+	}
+	var values []string
+	for _, item := range list {
+		if str, ok := coerceString(item); ok {
+			values = append(values, str)
+		}
+	}
+	return values, true
+}
+
+// coerceImages reads an object of image category -> list of images. An image is an object holding its link
+// and description under one of the given keys, or its link alone. Images without link are dropped.
+func coerceImages(value interface{}, linkKeys []string, descriptionKeys []string) (map[string][]Image, bool) {
+	categories, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, value == nil
+	}
+	var images map[string][]Image
+	for category, categoryImages := range categories {
+		list, ok := categoryImages.([]interface{})
+		if !ok {
+			list = []interface{}{categoryImages}
+		}
+		for _, item := range list {
+			var image Image
+			if imageObject, ok := item.(map[string]interface{}); ok {
+				if link, ok := lookupFirstPath(imageObject, linkKeys); ok {
+					image.Link, _ = coerceString(link)
+				}
+				if description, ok := lookupFirstPath(imageObject, descriptionKeys); ok {
+					image.Description, _ = coerceString(description)
+				}
+			} else {
+				image.Link, _ = coerceString(item)
+			}
+			if image.Link == "" {
+				continue
+			}
+			if images == nil {
+				images = make(map[string][]Image)
+			}
+			images[category] = append(images[category], image)
+		}
+	}
+	return images, true
+}
+
+// flexString decodes any JSON scalar into a trimmed string, other values decode to an empty string.
+type flexString string
+
+func (s *flexString) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	str, _ := coerceString(value)
+	*s = flexString(str)
+	return nil
+}
+
+// flexInt decodes an integer or a string holding one, other values decode to zero.
+type flexInt int
+
+func (i *flexInt) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	number, _ := coerceInt(value)
+	*i = flexInt(number)
+	return nil
+}
+
+// flexFloat decodes a number or a string holding one, other values decode to zero.
+type flexFloat float64
+
+func (f *flexFloat) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	number, _ := coerceFloat(value)
+	*f = flexFloat(number)
+	return nil
+}
+
+// flexStringList decodes a list of scalars or a single scalar, other values decode to an empty list.
+type flexStringList []string
+
+func (l *flexStringList) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	values, _ := coerceStringList(value)
+	*l = values
+	return nil
+}
@@ -0,0 +1,222 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCoerceScalars(t *testing.T) {
+	testCases := []struct {
+		description     string
+		value           interface{}
+		expectedString  string
+		expectedFloat   float64
+		expectedInt     int
+		expectedStrOK   bool
+		expectedFloatOK bool
+		expectedIntOK   bool
+	}{
+		{
+			description: "null",
+			value:       nil,
+		},
+		{
+			description: "blank string",
+			value:       "  ",
+		},
+		{
+			description:    "text",
+			value:          " Beach Villas ",
+			expectedString: "Beach Villas",
+			expectedStrOK:  true,
+		},
+		{
+			description:     "integer",
+			value:           5432.0,
+			expectedString:  "5432",
+			expectedFloat:   5432,
+			expectedInt:     5432,
+			expectedStrOK:   true,
+			expectedFloatOK: true,
+			expectedIntOK:   true,
+		},
+		{
+			description:     "integer as string",
+			value:           " 5432 ",
+			expectedString:  "5432",
+			expectedFloat:   5432,
+			expectedInt:     5432,
+			expectedStrOK:   true,
+			expectedFloatOK: true,
+			expectedIntOK:   true,
+		},
+		{
+			description:     "fractional number as string",
+			value:           "1.264751",
+			expectedString:  "1.264751",
+			expectedFloat:   1.264751,
+			expectedStrOK:   true,
+			expectedFloatOK: true,
+		},
+		{
+			description:     "large number is not written in exponent form",
+			value:           12345678.0,
+			expectedString:  "12345678",
+			expectedFloat:   12345678,
+			expectedInt:     12345678,
+			expectedStrOK:   true,
+			expectedFloatOK: true,
+			expectedIntOK:   true,
+		},
+		{
+			description:    "not a number",
+			value:          "NaN",
+			expectedString: "NaN",
+			expectedStrOK:  true,
+		},
+		{
+			description:    "boolean",
+			value:          true,
+			expectedString: "true",
+			expectedStrOK:  true,
+		},
+		{
+			description: "object",
+			value:       map[string]interface{}{"lat": 1.2},
+		},
+		{
+			description: "list",
+			value:       []interface{}{"5432"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			str, ok := coerceString(tc.value)
+			assert.Equal(t, tc.expectedString, str)
+			assert.Equal(t, tc.expectedStrOK, ok)
+			number, ok := coerceFloat(tc.value)
+			assert.Equal(t, tc.expectedFloat, number)
+			assert.Equal(t, tc.expectedFloatOK, ok)
+			integer, ok := coerceInt(tc.value)
+			assert.Equal(t, tc.expectedInt, integer)
+			assert.Equal(t, tc.expectedIntOK, ok)
+		})
+	}
+}
+
+func TestCoerceStringList(t *testing.T) {
+	testCases := []struct {
+		description    string
+		value          interface{}
+		expectedValues []string
+		expectedOK     bool
+	}{
+		{
+			description: "null",
+			value:       nil,
+			expectedOK:  true,
+		},
+		{
+			description:    "list of scalars",
+			value:          []interface{}{" Pool ", 24.0, nil, "", map[string]interface{}{}, "WiFi"},
+			expectedValues: []string{"Pool", "24", "WiFi"},
+			expectedOK:     true,
+		},
+		{
+			description:    "single scalar",
+			value:          "Pool",
+			expectedValues: []string{"Pool"},
+			expectedOK:     true,
+		},
+		{
+			description: "object",
+			value:       map[string]interface{}{"general": []interface{}{"Pool"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			values, ok := coerceStringList(tc.value)
+			assert.Equal(t, tc.expectedValues, values)
+			assert.Equal(t, tc.expectedOK, ok)
+		})
+	}
+}
+
+func TestCoerceImages(t *testing.T) {
+	testCases := []struct {
+		description    string
+		value          string
+		expectedImages map[string][]Image
+		expectedOK     bool
+	}{
+		{
+			description: "null",
+			value:       `null`,
+			expectedOK:  true,
+		},
+		{
+			description: "images of each category",
+			value: `{
+				"rooms": [{"url": " https://example.com/1.jpg ", "description": 12}, {"description": "no link"}],
+				"site": [null, "https://example.com/2.jpg"],
+				"amenities": {"url": "https://example.com/3.jpg"},
+				"empty": []
+			}`,
+			expectedImages: map[string][]Image{
+				"rooms":     {{Link: "https://example.com/1.jpg", Description: "12"}},
+				"site":      {{Link: "https://example.com/2.jpg"}},
+				"amenities": {{Link: "https://example.com/3.jpg"}},
+			},
+			expectedOK: true,
+		},
+		{
+			description: "list instead of categories",
+			value:       `[{"url": "https://example.com/1.jpg"}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var value interface{}
+			assert.Nil(t, json.Unmarshal([]byte(tc.value), &value))
+			images, ok := coerceImages(value, []string{"url"}, []string{"description"})
+			assert.Equal(t, tc.expectedImages, images)
+			assert.Equal(t, tc.expectedOK, ok)
+		})
+	}
+}
+
+func TestAdaptersNormalizeUnexpectedShapes(t *testing.T) {
+	adapters := map[string]SupplierAdapter{
+		"acme":       &acmeAdapter{},
+		"patagonia":  &patagoniaAdapter{},
+		"paperflies": &paperfliesAdapter{},
+		"default":    newMappingAdapter(defaultSupplierMapping, logging.LogrusLogger()),
+	}
+	values := []string{`null`, `""`, `"text"`, `"12.5"`, `-1`, `1e400`, `true`, `[]`, `[null, {}, [1]]`, `{}`, `{"a": {"b": null}}`}
+	keys := []string{
+		"Id", "DestinationId", "Name", "Latitude", "Longitude", "Address", "Facilities",
+		"id", "destination", "lat", "lng", "amenities", "images",
+		"hotel_id", "destination_id", "hotel_name", "location", "details", "booking_conditions",
+	}
+
+	for name, adapter := range adapters {
+		for _, key := range keys {
+			for _, value := range values {
+				record := `{"Id": "a", "id": "a", "hotel_id": "a", "DestinationId": 1, "destination": 1, "destination_id": 1, "` + key + `": ` + value + `}`
+				assert.NotPanics(t, func() {
+					adapter.Normalize(json.RawMessage(record))
+				}, "%s adapter with %s", name, record)
+			}
+		}
+		for _, record := range []string{`null`, `[]`, `"hotel"`, `12`, `{}`} {
+			assert.NotPanics(t, func() {
+				adapter.Normalize(json.RawMessage(record))
+			}, "%s adapter with %s", name, record)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package hotel_service
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	defaultDescriptionSimilarityThreshold = 0.8
+	descriptionShingleSize                = 3
+)
+
+// normalizeDescriptionText trims the description and collapses every whitespace run into a single space.
+func normalizeDescriptionText(description string) string {
+	return strings.Join(strings.Fields(description), " ")
+}
+
+// descriptionShingles returns the set of word n-grams of a description, ignoring case and punctuation.
+func descriptionShingles(description string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	shingles := make(map[string]bool)
+	if len(words) < descriptionShingleSize {
+		if len(words) > 0 {
+			shingles[strings.Join(words, " ")] = true
+		}
+		return shingles
+	}
+	for i := 0; i+descriptionShingleSize <= len(words); i++ {
+		shingles[strings.Join(words[i:i+descriptionShingleSize], " ")] = true
+	}
+	return shingles
+}
+
+// descriptionSimilarity is the overlap coefficient of the shingles of both descriptions, so a description
+// that is a reworded or truncated copy of another one scores close to 1.
+func descriptionSimilarity(a map[string]bool, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for shingle := range a {
+		if b[shingle] {
+			common++
+		}
+	}
+	smallest := len(a)
+	if len(b) < smallest {
+		smallest = len(b)
+	}
+	return float64(common) / float64(smallest)
+}
+
+// dedupeDescriptions keeps one description per group of similar descriptions, preferring the longest one.
+// The order of first appearance is kept, a non-positive threshold uses the default one.
+func dedupeDescriptions(descriptions []string, threshold float64) []string {
+	if threshold <= 0 {
+		threshold = defaultDescriptionSimilarityThreshold
+	}
+	var kept []string
+	var keptShingles []map[string]bool
+	for _, description := range descriptions {
+		description = normalizeDescriptionText(description)
+		if description == "" {
+			continue
+		}
+		shingles := descriptionShingles(description)
+		duplicate := false
+		for i := range kept {
+			if descriptionSimilarity(shingles, keptShingles[i]) >= threshold {
+				if len(description) > len(kept[i]) {
+					kept[i] = description
+					keptShingles[i] = shingles
+				}
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			kept = append(kept, description)
+			keptShingles = append(keptShingles, shingles)
+		}
+	}
+	return kept
+}
+
+// selectDescriptions deduplicates descriptions, with MergeStrategyBest the longest one is returned as the
+// description and the others as alternates.
+func selectDescriptions(descriptions []string, strategy MergeStrategy, threshold float64) ([]string, []string) {
+	distinct := dedupeDescriptions(descriptions, threshold)
+	if strategy != MergeStrategyBest || len(distinct) <= 1 {
+		return distinct, nil
+	}
+
+	best := 0
+	for i, description := range distinct {
+		if len(description) > len(distinct[best]) {
+			best = i
+		}
+	}
+	alternates := make([]string, 0, len(distinct)-1)
+	alternates = append(alternates, distinct[:best]...)
+	alternates = append(alternates, distinct[best+1:]...)
+	return []string{distinct[best]}, alternates
+}
+
+// mergeDescriptions merges the descriptions of several suppliers ordered by priority and records the supplier
+// of every kept description. With MergeStrategyPriority only the first supplier having a description is used.
+func mergeDescriptions(
+	lists [][]string,
+	sources []FieldSource,
+	strategy MergeStrategy,
+	threshold float64,
+) ([]string, []string, map[string]FieldSource) {
+	var collected []string
+	collectedSources := make(map[string]FieldSource)
+	for i, list := range lists {
+		if strategy == MergeStrategyPriority && len(collected) > 0 {
+			break
+		}
+		for _, description := range list {
+			description = normalizeDescriptionText(description)
+			if description == "" {
+				continue
+			}
+			if _, exists := collectedSources[description]; !exists {
+				collectedSources[description] = sources[i]
+			}
+			collected = append(collected, description)
+		}
+	}
+
+	descriptions, alternates := selectDescriptions(collected, strategy, threshold)
+	elementSources := make(map[string]FieldSource)
+	for _, description := range append(append([]string{}, descriptions...), alternates...) {
+		elementSources[description] = collectedSources[description]
+	}
+	return descriptions, alternates, elementSources
+}
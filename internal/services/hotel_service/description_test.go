@@ -0,0 +1,92 @@
+package hotel_service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSelectDescriptions(t *testing.T) {
+	acmeDescription := "Enjoy sophisticated waterfront living at the new InterContinental Singapore Robertson Quay."
+	paperfliesDescription := "Enjoy sophisticated waterfront living at the new InterContinental Singapore Robertson Quay, " +
+		"luxury's preferred address nestled in the heart of Robertson Quay along the Singapore River."
+	unrelatedDescription := "This sleek high-rise property is 10 minutes' walk from Shinjuku train station."
+
+	testCases := []struct {
+		description          string
+		descriptions         []string
+		strategy             MergeStrategy
+		threshold            float64
+		expectedDescriptions []string
+		expectedAlternates   []string
+	}{
+		{
+			description:          "collapse whitespace and drop empty descriptions",
+			descriptions:         []string{"  Located in\tthe heart \n of town. ", "", "Located in the heart of town."},
+			strategy:             MergeStrategyUnion,
+			expectedDescriptions: []string{"Located in the heart of town."},
+		},
+		{
+			description:          "near duplicates keep the longest wording",
+			descriptions:         []string{acmeDescription, unrelatedDescription, paperfliesDescription},
+			strategy:             MergeStrategyUnion,
+			expectedDescriptions: []string{paperfliesDescription, unrelatedDescription},
+		},
+		{
+			description:          "case and punctuation do not matter",
+			descriptions:         []string{"Close to the MRT station!", "close to the mrt station"},
+			strategy:             MergeStrategyUnion,
+			expectedDescriptions: []string{"Close to the MRT station!"},
+		},
+		{
+			description:          "a high threshold keeps truncated descriptions apart",
+			descriptions:         []string{"Close to the MRT station and the river.", "Close to the MRT station, with a pool and a gym."},
+			strategy:             MergeStrategyUnion,
+			threshold:            0.9,
+			expectedDescriptions: []string{"Close to the MRT station and the river.", "Close to the MRT station, with a pool and a gym."},
+		},
+		{
+			description:          "best strategy keeps the longest description and the others as alternates",
+			descriptions:         []string{unrelatedDescription, acmeDescription, paperfliesDescription},
+			strategy:             MergeStrategyBest,
+			expectedDescriptions: []string{paperfliesDescription},
+			expectedAlternates:   []string{unrelatedDescription},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			descriptions, alternates := selectDescriptions(tc.descriptions, tc.strategy, tc.threshold)
+			assert.Equal(t, tc.expectedDescriptions, descriptions)
+			assert.Equal(t, tc.expectedAlternates, alternates)
+		})
+	}
+}
+
+func TestMergeHotelDataDescriptions(t *testing.T) {
+	fetchedAt := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	storedHotel := Hotel{
+		ID:          "SjyX",
+		Description: []string{"Enjoy sophisticated waterfront living at the new InterContinental."},
+		Provenance: &HotelProvenance{Elements: map[string]map[string]FieldSource{
+			MergeFieldDescription: {"Enjoy sophisticated waterfront living at the new InterContinental.": {Supplier: "acme", FetchedAt: fetchedAt}},
+		}},
+	}
+	freshHotel := Hotel{
+		ID:          "SjyX",
+		Description: []string{"Enjoy sophisticated   waterfront living at the new InterContinental, along the Singapore River."},
+		Provenance: &HotelProvenance{Elements: map[string]map[string]FieldSource{
+			MergeFieldDescription: {"Enjoy sophisticated waterfront living at the new InterContinental, along the Singapore River.": {Supplier: "paperflies", FetchedAt: fetchedAt}},
+		}},
+	}
+
+	hotelService := &hotelServiceImpl{mergePolicy: DefaultMergePolicy(), descriptionThreshold: defaultDescriptionSimilarityThreshold}
+	currentHotelData := map[string]Hotel{"SjyX": storedHotel}
+	hotelService.mergeHotelData(freshHotel, currentHotelData)
+
+	hotel := currentHotelData["SjyX"]
+	assert.Equal(t, []string{"Enjoy sophisticated waterfront living at the new InterContinental, along the Singapore River."}, hotel.Description)
+	assert.Equal(t, map[string]FieldSource{
+		"Enjoy sophisticated waterfront living at the new InterContinental, along the Singapore River.": {Supplier: "paperflies", FetchedAt: fetchedAt},
+	}, hotel.Provenance.Elements[MergeFieldDescription])
+}
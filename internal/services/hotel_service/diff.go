@@ -0,0 +1,152 @@
+package hotel_service
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+// CatalogDiff lists the hotels added and removed by an update and the field changes of the other hotels.
+type CatalogDiff struct {
+	Added   []string      `json:"added"`
+	Removed []string      `json:"removed"`
+	Changed []HotelChange `json:"changed"`
+}
+
+type HotelChange struct {
+	HotelID string        `json:"hotel_id"`
+	Fields  []FieldChange `json:"fields"`
+}
+
+// FieldChange holds the previous and new value of a scalar field, or the elements added to and removed from
+// a list field. Images are compared by link, list order is ignored.
+type FieldChange struct {
+	Field   string      `json:"field"`
+	Before  interface{} `json:"before,omitempty"`
+	After   interface{} `json:"after,omitempty"`
+	Added   []string    `json:"added,omitempty"`
+	Removed []string    `json:"removed,omitempty"`
+}
+
+// DiffSummary is the size of a CatalogDiff.
+type DiffSummary struct {
+	HotelsAdded   int `json:"hotels_added"`
+	HotelsRemoved int `json:"hotels_removed"`
+	HotelsChanged int `json:"hotels_changed"`
+	FieldsChanged int `json:"fields_changed"`
+}
+
+func (d CatalogDiff) Summary() DiffSummary {
+	summary := DiffSummary{HotelsAdded: len(d.Added), HotelsRemoved: len(d.Removed), HotelsChanged: len(d.Changed)}
+	for _, change := range d.Changed {
+		summary.FieldsChanged += len(change.Fields)
+	}
+	return summary
+}
+
+func diffCatalogs(previous map[string]Hotel, current map[string]Hotel) CatalogDiff {
+	diff := CatalogDiff{Added: []string{}, Removed: []string{}, Changed: []HotelChange{}}
+	for _, id := range sortedHotelIds(current) {
+		previousHotel, exists := previous[id]
+		if !exists {
+			diff.Added = append(diff.Added, id)
+			continue
+		}
+		if fields := diffHotel(previousHotel, current[id]); len(fields) > 0 {
+			diff.Changed = append(diff.Changed, HotelChange{HotelID: id, Fields: fields})
+		}
+	}
+	for _, id := range sortedHotelIds(previous) {
+		if _, exists := current[id]; !exists {
+			diff.Removed = append(diff.Removed, id)
+		}
+	}
+	return diff
+}
+
+// diffHotel compares every merged field, provenance is not part of the diff.
+func diffHotel(previous Hotel, current Hotel) []FieldChange {
+	var changes []FieldChange
+	addScalar := func(field string, before interface{}, after interface{}) {
+		if before != after {
+			changes = append(changes, FieldChange{Field: field, Before: before, After: after})
+		}
+	}
+	addList := func(field string, before []string, after []string) {
+		added, removed := diffStringSets(before, after)
+		if len(added) > 0 || len(removed) > 0 {
+			changes = append(changes, FieldChange{Field: field, Added: added, Removed: removed})
+		}
+	}
+
+	addScalar(MergeFieldDestinationID, previous.DestinationID, current.DestinationID)
+	addScalar(MergeFieldHotelName, previous.HotelName, current.HotelName)
+	addScalar(MergeFieldLat, previous.Location.Lat, current.Location.Lat)
+	addScalar(MergeFieldLng, previous.Location.Long, current.Location.Long)
+	addScalar(MergeFieldAddress, previous.Location.Address, current.Location.Address)
+	addScalar(MergeFieldCity, previous.Location.City, current.Location.City)
+	addScalar(MergeFieldCountry, previous.Location.Country, current.Location.Country)
+	addList(MergeFieldDescription, previous.Description, current.Description)
+	addList("description_alternates", previous.DescriptionAlternates, current.DescriptionAlternates)
+	addList(ProvenanceFieldGeneralAmenities, previous.Amenities.General, current.Amenities.General)
+	addList(ProvenanceFieldRoomAmenities, previous.Amenities.Room, current.Amenities.Room)
+	addList(MergeFieldImages, imageLinks(previous.Images), imageLinks(current.Images))
+	addList(MergeFieldBookingConditions, previous.BookingCondition, current.BookingCondition)
+	addScalar("inactive", previous.Inactive, current.Inactive)
+	return changes
+}
+
+func diffStringSets(before []string, after []string) ([]string, []string) {
+	beforeSet := make(map[string]bool, len(before))
+	for _, value := range before {
+		beforeSet[value] = true
+	}
+	afterSet := make(map[string]bool, len(after))
+	for _, value := range after {
+		afterSet[value] = true
+	}
+	var added, removed []string
+	for _, value := range after {
+		if !beforeSet[value] {
+			added = append(added, value)
+			beforeSet[value] = true
+		}
+	}
+	for _, value := range before {
+		if !afterSet[value] {
+			removed = append(removed, value)
+			afterSet[value] = true
+		}
+	}
+	return added, removed
+}
+
+func imageLinks(images map[string][]Image) []string {
+	var links []string
+	for _, categoryImages := range images {
+		for _, image := range categoryImages {
+			links = append(links, image.Link)
+		}
+	}
+	sort.Strings(links)
+	return links
+}
+
+// cloneHotels deep copies hotels so they can be compared with the result of a merge, which updates them in place.
+func cloneHotels(hotels map[string]Hotel) (map[string]Hotel, error) {
+	data, err := json.Marshal(hotels)
+	if err != nil {
+		return nil, err
+	}
+	var clone map[string]Hotel
+	if err := json.Unmarshal(data, &clone); err != nil {
+		return nil, err
+	}
+	if clone == nil {
+		clone = map[string]Hotel{}
+	}
+	for id, hotel := range clone {
+		hotel.ID = id
+		clone[id] = hotel
+	}
+	return clone, nil
+}
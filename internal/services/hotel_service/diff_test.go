@@ -0,0 +1,77 @@
+package hotel_service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDiffCatalogs(t *testing.T) {
+	previous := map[string]Hotel{
+		"iJhz": {
+			ID: "iJhz", DestinationID: 5432, HotelName: "Beach Villas Singapore",
+			Location:  Location{Lat: 1.264751, Country: "SG"},
+			Amenities: Amenities{General: []string{"pool", "wifi"}},
+			Images: map[string][]Image{
+				"rooms": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/2.jpg"}},
+			},
+		},
+		"SjyX": {ID: "SjyX", DestinationID: 5432, HotelName: "InterContinental"},
+		"f8c9": {ID: "f8c9", DestinationID: 1122, HotelName: "Hilton Tokyo Shinjuku"},
+	}
+
+	testCases := []struct {
+		description  string
+		current      map[string]Hotel
+		expectedDiff CatalogDiff
+	}{
+		{
+			description:  "no change",
+			current:      previous,
+			expectedDiff: CatalogDiff{Added: []string{}, Removed: []string{}, Changed: []HotelChange{}},
+		},
+		{
+			description: "hotels added, removed and changed",
+			current: map[string]Hotel{
+				"iJhz": {
+					ID: "iJhz", DestinationID: 5432, HotelName: "Beach Villas",
+					Location:  Location{Lat: 1.264751, Country: "SG"},
+					Amenities: Amenities{General: []string{"wifi", "childcare"}},
+					Images: map[string][]Image{
+						"site": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/2.jpg"}},
+					},
+				},
+				"SjyX": {ID: "SjyX", DestinationID: 5432, HotelName: "InterContinental"},
+				"a1b2": {ID: "a1b2", DestinationID: 5432},
+			},
+			expectedDiff: CatalogDiff{
+				Added:   []string{"a1b2"},
+				Removed: []string{"f8c9"},
+				Changed: []HotelChange{{
+					HotelID: "iJhz",
+					Fields: []FieldChange{
+						{Field: MergeFieldHotelName, Before: "Beach Villas Singapore", After: "Beach Villas"},
+						{Field: ProvenanceFieldGeneralAmenities, Added: []string{"childcare"}, Removed: []string{"pool"}},
+					},
+				}},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expectedDiff, diffCatalogs(previous, tc.current))
+		})
+	}
+}
+
+func TestCatalogDiffSummary(t *testing.T) {
+	diff := CatalogDiff{
+		Added:   []string{"a1b2"},
+		Removed: []string{},
+		Changed: []HotelChange{
+			{HotelID: "iJhz", Fields: []FieldChange{{Field: MergeFieldHotelName}, {Field: MergeFieldImages}}},
+			{HotelID: "SjyX", Fields: []FieldChange{{Field: MergeFieldLat}}},
+		},
+	}
+	assert.Equal(t, DiffSummary{HotelsAdded: 1, HotelsChanged: 2, FieldsChanged: 3}, diff.Summary())
+}
@@ -0,0 +1,162 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"ascenda-loyalty-assignment/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy retries the supplier requests failing with a network error, a 5xx or a 429 status.
+// The delay before attempt n+1 is InitialBackoffMs * 2^(n-1), capped at MaxBackoffMs, of which a random
+// half is kept as jitter. A Retry-After header raises the delay, a Retry-After above MaxBackoffMs stops the
+// retries as the supplier would not answer before.
+type RetryPolicy struct {
+	MaxAttempts      int `json:"max_attempts"`
+	InitialBackoffMs int `json:"initial_backoff_ms"`
+	MaxBackoffMs     int `json:"max_backoff_ms"`
+}
+
+// FetchPolicy groups the settings of the supplier requests.
+type FetchPolicy struct {
+	Retry          RetryPolicy          `json:"retry"`
+	CircuitBreaker CircuitBreakerPolicy `json:"circuit_breaker"`
+	PartialFailure PartialFailurePolicy `json:"partial_failure"`
+}
+
+func LoadFetchPolicy(filePath string) (FetchPolicy, error) {
+	data, err := utils.ReadJSONFile(filePath)
+	if err != nil {
+		return FetchPolicy{}, err
+	}
+	var policy FetchPolicy
+	err = json.Unmarshal(data, &policy)
+	if err != nil {
+		return FetchPolicy{}, fmt.Errorf("invalid fetch policy file %s: %w", filePath, err)
+	}
+	if policy.Retry.MaxAttempts < 0 || policy.Retry.InitialBackoffMs < 0 || policy.Retry.MaxBackoffMs < 0 {
+		return FetchPolicy{}, fmt.Errorf("retry settings must not be negative")
+	}
+	if policy.CircuitBreaker.FailureThreshold < 0 || policy.CircuitBreaker.OpenSeconds < 0 {
+		return FetchPolicy{}, fmt.Errorf("circuit breaker settings must not be negative")
+	}
+	if policy.PartialFailure.MinSuccessfulSuppliers < 0 {
+		return FetchPolicy{}, fmt.Errorf("min_successful_suppliers must not be negative")
+	}
+	return policy, nil
+}
+
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	backoff := time.Duration(p.InitialBackoffMs) * time.Millisecond
+	maxBackoff := time.Duration(p.MaxBackoffMs) * time.Millisecond
+	for i := 1; i < attempt && (maxBackoff <= 0 || backoff < maxBackoff); i++ {
+		backoff *= 2
+	}
+	if maxBackoff > 0 && backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	if backoff <= 0 {
+		return 0
+	}
+	half := backoff / 2
+	return half + time.Duration(rand.Int63n(int64(backoff-half)+1))
+}
+
+func (p RetryPolicy) exceedsMaxBackoff(delay time.Duration) bool {
+	maxBackoff := time.Duration(p.MaxBackoffMs) * time.Millisecond
+	return maxBackoff > 0 && delay > maxBackoff
+}
+
+// retryingClient is an HTTPClient retrying the requests of a supplier, it only suits requests without body.
+type retryingClient struct {
+	client   HTTPClient
+	policy   RetryPolicy
+	logger   logging.Logger
+	supplier string
+	// fetch, when set, records the attempts and the last status code.
+	fetch *supplierFetch
+}
+
+func (c *retryingClient) Do(req *http.Request) (*http.Response, error) {
+	attempts := c.policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	for attempt := 1; ; attempt++ {
+		resp, err := c.client.Do(req)
+		if c.fetch != nil {
+			c.fetch.attempts = attempt
+			if err == nil {
+				c.fetch.statusCode = resp.StatusCode
+			}
+		}
+		if attempt >= attempts || !isRetryable(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		delay := c.policy.backoff(attempt)
+		var reason string
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = fmt.Sprintf("status code %d", resp.StatusCode)
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok && retryAfter > delay {
+				if c.policy.exceedsMaxBackoff(retryAfter) {
+					c.logger.Warn(fmt.Sprintf("Not retrying supplier %s after attempt %d/%d failed: %s, it asks to retry in %s", c.supplier, attempt, attempts, reason, retryAfter))
+					return resp, err
+				}
+				delay = retryAfter
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}
+		c.logger.Warn(fmt.Sprintf("Retrying supplier %s in %s after attempt %d/%d failed: %s", c.supplier, delay, attempt, attempts, reason))
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// isRetryable tells whether a request failed with a network error, a 5xx or a 429 status,
+// requests whose context is done are never retried.
+func isRetryable(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+}
+
+// parseRetryAfter reads a Retry-After header given either in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	if delay := date.Sub(now); delay > 0 {
+		return delay, true
+	}
+	return 0, true
+}
@@ -0,0 +1,208 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// SequenceHTTPClient returns its responses and errors in order, one per call, repeating the last one.
+type SequenceHTTPClient struct {
+	Responses []*http.Response
+	Errors    []error
+	Calls     int
+}
+
+func (m *SequenceHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	i := m.Calls
+	if i >= len(m.Responses) {
+		i = len(m.Responses) - 1
+	}
+	m.Calls++
+	if m.Errors[i] != nil {
+		return nil, m.Errors[i]
+	}
+	resp := *m.Responses[i]
+	resp.Body = io.NopCloser(strings.NewReader(`[]`))
+	return &resp, nil
+}
+
+func newSequenceHTTPClient(steps ...interface{}) *SequenceHTTPClient {
+	client := &SequenceHTTPClient{}
+	for _, step := range steps {
+		switch step := step.(type) {
+		case error:
+			client.Responses = append(client.Responses, nil)
+			client.Errors = append(client.Errors, step)
+		case *http.Response:
+			client.Responses = append(client.Responses, step)
+			client.Errors = append(client.Errors, nil)
+		}
+	}
+	return client
+}
+
+func TestRetryingClient(t *testing.T) {
+	ok := &http.Response{StatusCode: http.StatusOK}
+	badGateway := &http.Response{StatusCode: http.StatusBadGateway}
+	tooManyRequests := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"0"}}}
+	notFound := &http.Response{StatusCode: http.StatusNotFound}
+	retryLater := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": {"60"}}}
+
+	testCases := []struct {
+		description    string
+		client         *SequenceHTTPClient
+		maxAttempts    int
+		expectedStatus int
+		expectedErr    bool
+		expectedCalls  int
+	}{
+		{
+			description:    "retry a 5xx status",
+			client:         newSequenceHTTPClient(badGateway, ok),
+			maxAttempts:    3,
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+		},
+		{
+			description:    "retry a 429 status",
+			client:         newSequenceHTTPClient(tooManyRequests, tooManyRequests, ok),
+			maxAttempts:    3,
+			expectedStatus: http.StatusOK,
+			expectedCalls:  3,
+		},
+		{
+			description:    "stop retrying when Retry-After exceeds the max backoff",
+			client:         newSequenceHTTPClient(retryLater, ok),
+			maxAttempts:    3,
+			expectedStatus: http.StatusTooManyRequests,
+			expectedCalls:  1,
+		},
+		{
+			description:    "retry a network error",
+			client:         newSequenceHTTPClient(fmt.Errorf("connection reset by peer"), ok),
+			maxAttempts:    3,
+			expectedStatus: http.StatusOK,
+			expectedCalls:  2,
+		},
+		{
+			description:    "return the last response once attempts are exhausted",
+			client:         newSequenceHTTPClient(badGateway),
+			maxAttempts:    3,
+			expectedStatus: http.StatusBadGateway,
+			expectedCalls:  3,
+		},
+		{
+			description:   "return the last error once attempts are exhausted",
+			client:        newSequenceHTTPClient(fmt.Errorf("connection refused")),
+			maxAttempts:   2,
+			expectedErr:   true,
+			expectedCalls: 2,
+		},
+		{
+			description:    "do not retry a 4xx status",
+			client:         newSequenceHTTPClient(notFound, ok),
+			maxAttempts:    3,
+			expectedStatus: http.StatusNotFound,
+			expectedCalls:  1,
+		},
+		{
+			description:    "single attempt without retry policy",
+			client:         newSequenceHTTPClient(badGateway, ok),
+			expectedStatus: http.StatusBadGateway,
+			expectedCalls:  1,
+		},
+	}
+
+	logger := logging.LogrusLogger()
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			client := &retryingClient{
+				client:   tc.client,
+				policy:   RetryPolicy{MaxAttempts: tc.maxAttempts, InitialBackoffMs: 1, MaxBackoffMs: 2},
+				logger:   logger,
+				supplier: "example",
+			}
+			req, _ := http.NewRequest(http.MethodGet, "https://example.com/hotels", nil)
+			resp, err := client.Do(req)
+			if tc.expectedErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, tc.expectedStatus, resp.StatusCode)
+			}
+			assert.Equal(t, tc.expectedCalls, tc.client.Calls)
+		})
+	}
+}
+
+func TestRetryingClientStopsOnCancel(t *testing.T) {
+	sequence := newSequenceHTTPClient(&http.Response{StatusCode: http.StatusServiceUnavailable})
+	client := &retryingClient{
+		client:   sequence,
+		policy:   RetryPolicy{MaxAttempts: 5, InitialBackoffMs: 60000, MaxBackoffMs: 60000},
+		logger:   logging.LogrusLogger(),
+		supplier: "example",
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "https://example.com/hotels", nil)
+
+	_, err := client.Do(req)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Equal(t, 1, sequence.Calls)
+}
+
+func TestRetryBackoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 5, InitialBackoffMs: 100, MaxBackoffMs: 300}
+	testCases := []struct {
+		attempt     int
+		expectedMin time.Duration
+		expectedMax time.Duration
+	}{
+		{attempt: 1, expectedMin: 50 * time.Millisecond, expectedMax: 100 * time.Millisecond},
+		{attempt: 2, expectedMin: 100 * time.Millisecond, expectedMax: 200 * time.Millisecond},
+		{attempt: 3, expectedMin: 150 * time.Millisecond, expectedMax: 300 * time.Millisecond},
+		{attempt: 10, expectedMin: 150 * time.Millisecond, expectedMax: 300 * time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		t.Run(fmt.Sprintf("attempt %d", tc.attempt), func(t *testing.T) {
+			for i := 0; i < 20; i++ {
+				backoff := policy.backoff(tc.attempt)
+				assert.GreaterOrEqual(t, backoff, tc.expectedMin)
+				assert.LessOrEqual(t, backoff, tc.expectedMax)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 11, 1, 8, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		description   string
+		value         string
+		expectedDelay time.Duration
+		expectedOk    bool
+	}{
+		{description: "seconds", value: "120", expectedDelay: 2 * time.Minute, expectedOk: true},
+		{description: "http date", value: "Fri, 01 Nov 2024 08:00:30 GMT", expectedDelay: 30 * time.Second, expectedOk: true},
+		{description: "past http date", value: "Fri, 01 Nov 2024 07:00:00 GMT", expectedDelay: 0, expectedOk: true},
+		{description: "missing", value: "", expectedOk: false},
+		{description: "invalid", value: "soon", expectedOk: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			delay, ok := parseRetryAfter(tc.value, now)
+			assert.Equal(t, tc.expectedOk, ok)
+			assert.Equal(t, tc.expectedDelay, delay)
+		})
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package hotel_service
+
+import "os"
+
+// Advisory file locks are only supported on unix, other platforms rely on the process lock.
+func lockFile(file *os.File) error {
+	return nil
+}
+
+func unlockFile(file *os.File) error {
+	return nil
+}
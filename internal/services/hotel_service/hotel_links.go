@@ -0,0 +1,197 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/utils"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	HotelLinkProposed  = "proposed"
+	HotelLinkConfirmed = "confirmed"
+	HotelLinkRejected  = "rejected"
+)
+
+var (
+	ErrHotelLinkNotFound = errors.New("hotel link not found")
+	ErrHotelLinkConflict = errors.New("supplier hotel id is already linked to another hotel")
+)
+
+// MatchSignals are the similarities the matcher found between two hotels, the distance and the address
+// similarity are only set when both hotels have coordinates or an address.
+type MatchSignals struct {
+	NameSimilarity    float64  `json:"name_similarity"`
+	DistanceMeters    *float64 `json:"distance_meters,omitempty"`
+	AddressSimilarity *float64 `json:"address_similarity,omitempty"`
+}
+
+// HotelLink maps the id a supplier uses for a hotel to the canonical id the hotel is stored under.
+// Proposed links are made by the matcher and only used once confirmed.
+type HotelLink struct {
+	ID              string        `json:"id"`
+	Supplier        string        `json:"supplier"`
+	SupplierHotelID string        `json:"supplier_hotel_id"`
+	CanonicalID     string        `json:"canonical_id"`
+	Status          string        `json:"status"`
+	Score           float64       `json:"score,omitempty"`
+	Signals         *MatchSignals `json:"signals,omitempty"`
+	ProposedAt      time.Time     `json:"proposed_at"`
+	DecidedAt       *time.Time    `json:"decided_at,omitempty"`
+}
+
+// HotelLinkStore is the cross-reference table of supplier hotel ids, kept in a JSON file.
+type HotelLinkStore struct {
+	mu       sync.Mutex
+	filePath string
+}
+
+func NewHotelLinkStore(filePath string) *HotelLinkStore {
+	return &HotelLinkStore{filePath: filePath}
+}
+
+// List returns the links with the given status, or every link when status is empty.
+func (s *HotelLinkStore) List(status string) ([]HotelLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	links, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]HotelLink, 0, len(links))
+	for _, link := range links {
+		if status == "" || link.Status == status {
+			filtered = append(filtered, link)
+		}
+	}
+	return filtered, nil
+}
+
+// propose stores the links of the matcher and returns the stored ones, links already known for the same ids
+// are skipped.
+func (s *HotelLinkStore) propose(proposals []HotelLink) ([]HotelLink, error) {
+	if len(proposals) == 0 {
+		return nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	links, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(links))
+	for _, link := range links {
+		known[hotelLinkKey(link.Supplier, link.SupplierHotelID)+"\x00"+link.CanonicalID] = true
+	}
+	var proposed []HotelLink
+	for _, proposal := range proposals {
+		key := hotelLinkKey(proposal.Supplier, proposal.SupplierHotelID) + "\x00" + proposal.CanonicalID
+		if known[key] {
+			continue
+		}
+		known[key] = true
+		proposal.ID, err = newHotelLinkID()
+		if err != nil {
+			return nil, err
+		}
+		proposal.Status = HotelLinkProposed
+		links = append(links, proposal)
+		proposed = append(proposed, proposal)
+	}
+	if len(proposed) == 0 {
+		return nil, nil
+	}
+	if err := s.write(links); err != nil {
+		return nil, err
+	}
+	return proposed, nil
+}
+
+// decide confirms or rejects a link. Confirming a link rejects the other proposals for the same supplier
+// hotel id, it fails with ErrHotelLinkConflict when that id is already confirmed for another hotel.
+func (s *HotelLinkStore) decide(id string, status string) (HotelLink, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	links, err := s.read()
+	if err != nil {
+		return HotelLink{}, err
+	}
+	index := -1
+	for i, link := range links {
+		if link.ID == id {
+			index = i
+			break
+		}
+	}
+	if index == -1 {
+		return HotelLink{}, fmt.Errorf("%w: %s", ErrHotelLinkNotFound, id)
+	}
+
+	decidedAt := time.Now().UTC()
+	decided := links[index]
+	key := hotelLinkKey(decided.Supplier, decided.SupplierHotelID)
+	if status == HotelLinkConfirmed {
+		for i, link := range links {
+			if i == index || hotelLinkKey(link.Supplier, link.SupplierHotelID) != key {
+				continue
+			}
+			if link.Status == HotelLinkConfirmed {
+				return HotelLink{}, fmt.Errorf("%w: %s of %s is linked to %s", ErrHotelLinkConflict, link.SupplierHotelID, link.Supplier, link.CanonicalID)
+			}
+			if link.Status == HotelLinkProposed {
+				links[i].Status = HotelLinkRejected
+				links[i].DecidedAt = &decidedAt
+			}
+		}
+	}
+	decided.Status = status
+	decided.DecidedAt = &decidedAt
+	links[index] = decided
+	if err := s.write(links); err != nil {
+		return HotelLink{}, err
+	}
+	return decided, nil
+}
+
+func (s *HotelLinkStore) read() ([]HotelLink, error) {
+	data, err := os.ReadFile(s.filePath)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(data) == 0) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var links []HotelLink
+	if err := json.Unmarshal(data, &links); err != nil {
+		return nil, fmt.Errorf("invalid hotel links file %s: %w", s.filePath, err)
+	}
+	return links, nil
+}
+
+func (s *HotelLinkStore) write(links []HotelLink) error {
+	sort.SliceStable(links, func(i, j int) bool {
+		if links[i].Supplier != links[j].Supplier {
+			return links[i].Supplier < links[j].Supplier
+		}
+		return links[i].SupplierHotelID < links[j].SupplierHotelID
+	})
+	return utils.WriteJSONFile(s.filePath, links)
+}
+
+func hotelLinkKey(supplier string, supplierHotelID string) string {
+	return supplier + "\x00" + supplierHotelID
+}
+
+func newHotelLinkID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate hotel link id: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
@@ -0,0 +1,298 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHotelLinkStore(t *testing.T) {
+	store := NewHotelLinkStore(filepath.Join(t.TempDir(), "hotel_links.json"))
+	links, err := store.List("")
+	assert.Nil(t, err)
+	assert.Empty(t, links)
+
+	proposed, err := store.propose([]HotelLink{
+		{Supplier: "acme", SupplierHotelID: "ic01", CanonicalID: "SjyX", Score: 0.9},
+		{Supplier: "acme", SupplierHotelID: "ic01", CanonicalID: "iJhz", Score: 0.85},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, proposed, 2)
+	assert.NotEmpty(t, proposed[0].ID)
+	assert.Equal(t, HotelLinkProposed, proposed[0].Status)
+
+	// the matcher proposing the same link again does not duplicate it
+	proposed, err = store.propose([]HotelLink{{Supplier: "acme", SupplierHotelID: "ic01", CanonicalID: "SjyX"}})
+	assert.Nil(t, err)
+	assert.Empty(t, proposed)
+	links, err = store.List(HotelLinkProposed)
+	assert.Nil(t, err)
+	assert.Len(t, links, 2)
+
+	var first, second HotelLink
+	for _, link := range links {
+		if link.CanonicalID == "SjyX" {
+			first = link
+		} else {
+			second = link
+		}
+	}
+	confirmed, err := store.decide(first.ID, HotelLinkConfirmed)
+	assert.Nil(t, err)
+	assert.Equal(t, HotelLinkConfirmed, confirmed.Status)
+	assert.NotNil(t, confirmed.DecidedAt)
+
+	// confirming a link rejects the other proposals for the same supplier hotel id
+	links, err = store.List(HotelLinkRejected)
+	assert.Nil(t, err)
+	assert.Len(t, links, 1)
+	assert.Equal(t, second.ID, links[0].ID)
+
+	_, err = store.decide(second.ID, HotelLinkConfirmed)
+	assert.True(t, errors.Is(err, ErrHotelLinkConflict))
+	_, err = store.decide("unknown", HotelLinkRejected)
+	assert.True(t, errors.Is(err, ErrHotelLinkNotFound))
+}
+
+func TestMatchPolicyScore(t *testing.T) {
+	policy := MatchPolicy{Enabled: true}
+	stored := Hotel{
+		ID:        "SjyX",
+		HotelName: "InterContinental Singapore Robertson Quay",
+		Location:  Location{Lat: 1.28967, Long: 103.837, Address: "1 Nanson Road"},
+	}
+	testCases := []struct {
+		description     string
+		hotel           Hotel
+		expectedMatched bool
+		expectedScore   float64
+	}{
+		{
+			description: "same name, address and location",
+			hotel: Hotel{
+				HotelName: "Intercontinental Singapore Robertson Quay",
+				Location:  Location{Lat: 1.28967, Long: 103.837, Address: "1 Nanson Rd"},
+			},
+			expectedMatched: true,
+			expectedScore:   0.933,
+		},
+		{
+			description:     "same name without location and address",
+			hotel:           Hotel{HotelName: "InterContinental Singapore, Robertson Quay"},
+			expectedMatched: true,
+			expectedScore:   1,
+		},
+		{
+			description: "same name too far away",
+			hotel: Hotel{
+				HotelName: "InterContinental Singapore Robertson Quay",
+				Location:  Location{Lat: 1.3, Long: 103.837},
+			},
+			expectedMatched: false,
+		},
+		{
+			description: "another hotel nearby",
+			hotel: Hotel{
+				HotelName: "Novotel Singapore on Kitchener",
+				Location:  Location{Lat: 1.2897, Long: 103.8371, Address: "181 Kitchener Road"},
+			},
+			expectedMatched: false,
+		},
+		{
+			description:     "missing name",
+			hotel:           Hotel{Location: Location{Lat: 1.28967, Long: 103.837}},
+			expectedMatched: false,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			score, _, matched := policy.score(newMatchCandidate(tc.hotel, nil), newMatchCandidate(stored, nil))
+			assert.Equal(t, tc.expectedMatched, matched)
+			if tc.expectedMatched {
+				assert.Equal(t, tc.expectedScore, score)
+			}
+		})
+	}
+}
+
+func TestMatchHotels(t *testing.T) {
+	acmeHotel := Hotel{
+		ID:            "ic01",
+		DestinationID: 5432,
+		HotelName:     "InterContinental Singapore Robertson Quay",
+		Location:      Location{Address: "1 Nanson Road"},
+	}
+	paperfliesHotel := Hotel{
+		ID:            "SjyX",
+		DestinationID: 5432,
+		HotelName:     "InterContinental Singapore Robertson Quay",
+		Location:      Location{Address: "1 Nanson Rd"},
+	}
+	storedHotel := func(hotel Hotel, supplier string) Hotel {
+		hotel.Provenance = newHotelProvenance()
+		hotel.Provenance.LastSeen[supplier] = time.Now()
+		return hotel
+	}
+	updatedData := func() []supplierHotelsData {
+		return []supplierHotelsData{
+			{supplier: Supplier{Name: "acme", Priority: 1}, hotels: []Hotel{acmeHotel}},
+			{supplier: Supplier{Name: "paperflies", Priority: 3}, hotels: []Hotel{paperfliesHotel}},
+		}
+	}
+	testCases := []struct {
+		description       string
+		currentHotelData  map[string]Hotel
+		links             []HotelLink
+		expectedProposals []string
+	}{
+		{
+			description:       "propose a hotel new to the catalog",
+			currentHotelData:  map[string]Hotel{"SjyX": storedHotel(paperfliesHotel, "paperflies")},
+			expectedProposals: []string{"acme/ic01 -> SjyX"},
+		},
+		{
+			description: "propose duplicates already stored to the hotel of the higher-priority supplier",
+			currentHotelData: map[string]Hotel{
+				"ic01": storedHotel(acmeHotel, "acme"),
+				"SjyX": storedHotel(paperfliesHotel, "paperflies"),
+			},
+			expectedProposals: []string{"acme/ic01 -> SjyX"},
+		},
+		{
+			description: "leave linked supplier ids to the admins",
+			currentHotelData: map[string]Hotel{
+				"ic01": storedHotel(acmeHotel, "acme"),
+				"SjyX": storedHotel(paperfliesHotel, "paperflies"),
+			},
+			links: []HotelLink{{Supplier: "acme", SupplierHotelID: "ic01", CanonicalID: "SjyX", Status: HotelLinkProposed}},
+		},
+		{
+			description: "do not propose a pair the other way round",
+			currentHotelData: map[string]Hotel{
+				"ic01": storedHotel(acmeHotel, "acme"),
+				"SjyX": storedHotel(paperfliesHotel, "paperflies"),
+			},
+			links: []HotelLink{{Supplier: "paperflies", SupplierHotelID: "SjyX", CanonicalID: "ic01", Status: HotelLinkProposed}},
+		},
+		{
+			description: "skip rejected pairs",
+			currentHotelData: map[string]Hotel{
+				"ic01": storedHotel(acmeHotel, "acme"),
+				"SjyX": storedHotel(paperfliesHotel, "paperflies"),
+			},
+			links: []HotelLink{{Supplier: "acme", SupplierHotelID: "ic01", CanonicalID: "SjyX", Status: HotelLinkRejected}},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			hotelService := &hotelServiceImpl{logger: logging.LogrusLogger(), matchPolicy: MatchPolicy{Enabled: true}}
+			var proposals []string
+			for _, link := range hotelService.matchHotels(updatedData(), tc.currentHotelData, tc.links) {
+				proposals = append(proposals, link.Supplier+"/"+link.SupplierHotelID+" -> "+link.CanonicalID)
+			}
+			assert.Equal(t, tc.expectedProposals, proposals)
+		})
+	}
+}
+
+func TestUpdateHotelsFromSuppliersHotelLinks(t *testing.T) {
+	payload := `[
+		{"Id": "ic01", "DestinationId": 5432, "Name": "InterContinental Singapore Robertson Quay", "Address": "1 Nanson Road"},
+		{"Id": "n3w1", "DestinationId": 5432, "Name": "New Hotel"}
+	]`
+	mockClient := &MockHTTPClient{Responses: map[string]*http.Response{}}
+	logger := logging.LogrusLogger()
+	wd, _ := os.Getwd()
+	repository := NewJSONHotelRepository(logger, copyTestDataFile(t, "test_sanitize_data.json"))
+	config := Config{
+		Repository:        repository,
+		SuppliersFilePath: filepath.Join(wd, "test_data", "test_suppliers.json"),
+		Adapters:          map[string]SupplierAdapter{"": &acmeAdapter{}},
+		HotelLinks:        NewHotelLinkStore(filepath.Join(t.TempDir(), "hotel_links.json")),
+		MatchPolicy:       MatchPolicy{Enabled: true},
+	}
+	hotelService := NewHotelService(logger, mockClient, context.Background(), config)
+	update := func() UpdateResult {
+		mockClient.Responses["https://example.com/hotels"] = &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(payload)),
+		}
+		result, err := hotelService.UpdateHotelsFromSuppliers(UpdateOptions{})
+		assert.Nil(t, err)
+		return result
+	}
+
+	// the matcher proposes a link, the hotel is stored under the supplier id until the link is confirmed
+	result := update()
+	assert.Equal(t, 2, result.Diff.HotelsAdded)
+	assert.Len(t, result.HotelLinksProposed, 1)
+	proposal := result.HotelLinksProposed[0]
+	assert.Equal(t, "example", proposal.Supplier)
+	assert.Equal(t, "ic01", proposal.SupplierHotelID)
+	assert.Equal(t, "SjyX", proposal.CanonicalID)
+
+	link, err := hotelService.ConfirmHotelLink(proposal.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, HotelLinkConfirmed, link.Status)
+
+	// the next update stores the supplier hotel under the canonical id and folds the alias hotel into it
+	result = update()
+	assert.Empty(t, result.HotelLinksProposed)
+	assert.Equal(t, 1, result.Diff.HotelsRemoved)
+	hotels, err := repository.Snapshot()
+	assert.Nil(t, err)
+	assert.NotContains(t, hotels, "ic01")
+	assert.Contains(t, hotels, "n3w1")
+	assert.Contains(t, hotels["SjyX"].Provenance.LastSeen, "example")
+
+	links, err := hotelService.ListHotelLinks(HotelLinkProposed)
+	assert.Nil(t, err)
+	assert.Empty(t, links)
+}
+
+func TestUpdateHotelsFromSuppliersProposesUnstoredLinksAgain(t *testing.T) {
+	payload := `[{"Id": "ic01", "DestinationId": 5432, "Name": "InterContinental Singapore Robertson Quay", "Address": "1 Nanson Road"}]`
+	mockClient := &MockHTTPClient{Responses: map[string]*http.Response{}}
+	logger := logging.LogrusLogger()
+	wd, _ := os.Getwd()
+	linksDir := filepath.Join(t.TempDir(), "links")
+	config := Config{
+		Repository:        NewJSONHotelRepository(logger, copyTestDataFile(t, "test_sanitize_data.json")),
+		SuppliersFilePath: filepath.Join(wd, "test_data", "test_suppliers.json"),
+		Adapters:          map[string]SupplierAdapter{"": &acmeAdapter{}},
+		HotelLinks:        NewHotelLinkStore(filepath.Join(linksDir, "hotel_links.json")),
+		MatchPolicy:       MatchPolicy{Enabled: true},
+	}
+	hotelService := NewHotelService(logger, mockClient, context.Background(), config)
+	update := func() UpdateResult {
+		mockClient.Responses["https://example.com/hotels"] = &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(payload)),
+		}
+		result, err := hotelService.UpdateHotelsFromSuppliers(UpdateOptions{})
+		assert.Nil(t, err)
+		return result
+	}
+
+	// the links directory is missing, the hotel is stored but its link cannot be
+	result := update()
+	assert.Equal(t, 1, result.Diff.HotelsAdded)
+	assert.Empty(t, result.HotelLinksProposed)
+	assert.Len(t, result.Warnings, 1)
+
+	// the stored hotel is still without link, so the next update proposes it again
+	assert.Nil(t, os.MkdirAll(linksDir, 0755))
+	result = update()
+	assert.Empty(t, result.Warnings)
+	assert.Len(t, result.HotelLinksProposed, 1)
+	assert.Equal(t, "ic01", result.HotelLinksProposed[0].SupplierHotelID)
+	assert.Equal(t, "SjyX", result.HotelLinksProposed[0].CanonicalID)
+}
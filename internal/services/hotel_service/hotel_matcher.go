@@ -0,0 +1,297 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/utils"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	defaultMatchMinScore          = 0.8
+	defaultMatchMaxDistanceMeters = 500.0
+	earthRadiusMeters             = 6371000.0
+	matchNameWeight               = 0.5
+	matchDistanceWeight           = 0.3
+	matchAddressWeight            = 0.2
+)
+
+// MatchPolicy tunes the matcher proposing links between hotels stored under different supplier ids.
+// Two hotels of the same destination match when the weighted average of their name similarity, closeness
+// and address similarity reaches MinScore. Hotels further apart than MaxDistanceMeters never match.
+// Zero values use the defaults.
+type MatchPolicy struct {
+	Enabled           bool    `json:"enabled"`
+	MinScore          float64 `json:"min_score"`
+	MaxDistanceMeters float64 `json:"max_distance_meters"`
+}
+
+func LoadMatchPolicy(filePath string) (MatchPolicy, error) {
+	data, err := utils.ReadJSONFile(filePath)
+	if err != nil {
+		return MatchPolicy{}, err
+	}
+	var policy MatchPolicy
+	err = json.Unmarshal(data, &policy)
+	if err != nil {
+		return MatchPolicy{}, fmt.Errorf("invalid hotel matching file %s: %w", filePath, err)
+	}
+	if policy.MinScore < 0 || policy.MinScore > 1 {
+		return MatchPolicy{}, fmt.Errorf("min_score must be between 0 and 1")
+	}
+	if policy.MaxDistanceMeters < 0 {
+		return MatchPolicy{}, fmt.Errorf("max_distance_meters must not be negative")
+	}
+	return policy, nil
+}
+
+func (p MatchPolicy) minScore() float64 {
+	if p.MinScore <= 0 {
+		return defaultMatchMinScore
+	}
+	return p.MinScore
+}
+
+func (p MatchPolicy) maxDistanceMeters() float64 {
+	if p.MaxDistanceMeters <= 0 {
+		return defaultMatchMaxDistanceMeters
+	}
+	return p.MaxDistanceMeters
+}
+
+// matchCandidate is a hotel the matcher compares, along with the suppliers reporting it.
+type matchCandidate struct {
+	hotel        Hotel
+	suppliers    map[string]bool
+	nameWords    map[string]bool
+	addressWords map[string]bool
+	// reported is set once a supplier reported the hotel in the update being matched.
+	reported bool
+}
+
+func newMatchCandidate(hotel Hotel, suppliers map[string]bool) *matchCandidate {
+	return &matchCandidate{
+		hotel:        hotel,
+		suppliers:    suppliers,
+		nameWords:    matchWords(hotel.HotelName),
+		addressWords: matchWords(hotel.Location.Address),
+	}
+}
+
+// score compares two hotels, the signals missing on either side are left out of the weighted average.
+func (p MatchPolicy) score(a *matchCandidate, b *matchCandidate) (float64, MatchSignals, bool) {
+	if len(a.nameWords) == 0 || len(b.nameWords) == 0 {
+		return 0, MatchSignals{}, false
+	}
+	signals := MatchSignals{NameSimilarity: roundScore(wordSimilarity(a.nameWords, b.nameWords))}
+	total, weights := signals.NameSimilarity*matchNameWeight, matchNameWeight
+
+	if hasCoordinates(a.hotel.Location) && hasCoordinates(b.hotel.Location) {
+		distance := math.Round(distanceMeters(a.hotel.Location, b.hotel.Location))
+		if distance > p.maxDistanceMeters() {
+			return 0, MatchSignals{}, false
+		}
+		signals.DistanceMeters = &distance
+		total += (1 - distance/p.maxDistanceMeters()) * matchDistanceWeight
+		weights += matchDistanceWeight
+	}
+	if len(a.addressWords) > 0 && len(b.addressWords) > 0 {
+		similarity := roundScore(wordSimilarity(a.addressWords, b.addressWords))
+		signals.AddressSimilarity = &similarity
+		total += similarity * matchAddressWeight
+		weights += matchAddressWeight
+	}
+
+	score := roundScore(total / weights)
+	return score, signals, score >= p.minScore()
+}
+
+// matchHotels proposes a link for every supplier hotel without link that matches a hotel of another supplier
+// stored or reported under another id. Suppliers are handled by priority, so the hotel of the highest-priority
+// supplier becomes the canonical one: a hotel already known does not match the hotels reported after it in
+// this update, they match it instead. Stored hotels are matched again on every update until their supplier id
+// is linked, so duplicates already in the catalog are proposed too. Supplier ids already linked are left to
+// the admins and pairs of hotels already linked, proposed or rejected are not proposed the other way round.
+func (h *hotelServiceImpl) matchHotels(updatedData []supplierHotelsData, currentHotelData map[string]Hotel, links []HotelLink) []HotelLink {
+	if !h.matchPolicy.Enabled {
+		return nil
+	}
+	linked := make(map[string]bool)
+	// pairs holds the hotels already linked, proposed or rejected either way round
+	pairs := make(map[string]bool)
+	addPair := func(id string, otherId string) {
+		pairs[id+"\x00"+otherId] = true
+		pairs[otherId+"\x00"+id] = true
+	}
+	for _, link := range links {
+		if link.Status != HotelLinkRejected {
+			linked[hotelLinkKey(link.Supplier, link.SupplierHotelID)] = true
+		}
+		addPair(link.SupplierHotelID, link.CanonicalID)
+	}
+
+	candidates := make(map[string]*matchCandidate, len(currentHotelData))
+	candidatesByDestination := make(map[int][]*matchCandidate)
+	addCandidate := func(candidate *matchCandidate) {
+		candidates[candidate.hotel.ID] = candidate
+		candidatesByDestination[candidate.hotel.DestinationID] = append(candidatesByDestination[candidate.hotel.DestinationID], candidate)
+	}
+	for _, id := range sortedHotelIds(currentHotelData) {
+		hotel := currentHotelData[id]
+		suppliers := make(map[string]bool)
+		if hotel.Provenance != nil {
+			for supplier := range hotel.Provenance.LastSeen {
+				suppliers[supplier] = true
+			}
+		}
+		addCandidate(newMatchCandidate(hotel, suppliers))
+	}
+
+	sortSupplierHotelsData(updatedData)
+	reportedIds := make(map[string]bool)
+	for _, supplierData := range updatedData {
+		for _, hotel := range supplierData.hotels {
+			reportedIds[hotel.ID] = true
+		}
+	}
+	var proposals []HotelLink
+	for _, supplierData := range updatedData {
+		supplier := supplierData.supplier.Name
+		for _, hotel := range supplierData.hotels {
+			candidate, known := candidates[hotel.ID]
+			if !known {
+				candidate = newMatchCandidate(hotel, map[string]bool{supplier: true})
+			}
+			key := hotelLinkKey(supplier, hotel.ID)
+			if !linked[key] {
+				reportedHotel := newMatchCandidate(hotel, nil)
+				var best *matchCandidate
+				var bestScore float64
+				var bestSignals MatchSignals
+				for _, other := range candidatesByDestination[hotel.DestinationID] {
+					// a hotel reported later in this update belongs to a lower-priority supplier, it is matched then
+					pending := reportedIds[other.hotel.ID] && !other.reported
+					if other == candidate || (known && pending) || other.suppliers[supplier] || pairs[hotel.ID+"\x00"+other.hotel.ID] {
+						continue
+					}
+					score, signals, matched := h.matchPolicy.score(reportedHotel, other)
+					if matched && score > bestScore {
+						best, bestScore, bestSignals = other, score, signals
+					}
+				}
+				if best != nil {
+					h.logger.Info(fmt.Sprintf("Hotel %s of supplier %s matches hotel %s with score %v", hotel.ID, supplier, best.hotel.ID, bestScore))
+					addPair(hotel.ID, best.hotel.ID)
+					signals := bestSignals
+					proposals = append(proposals, HotelLink{
+						Supplier:        supplier,
+						SupplierHotelID: hotel.ID,
+						CanonicalID:     best.hotel.ID,
+						Status:          HotelLinkProposed,
+						Score:           bestScore,
+						Signals:         &signals,
+						ProposedAt:      time.Now().UTC(),
+					})
+				}
+			}
+			candidate.suppliers[supplier] = true
+			candidate.reported = true
+			if !known {
+				addCandidate(candidate)
+			}
+		}
+	}
+	return proposals
+}
+
+// applyHotelLinks moves the supplier hotels to their canonical id following the confirmed links. A hotel
+// stored under a linked supplier id is folded into the canonical hotel once only the linked suppliers
+// reported it and none of them reports the old id any more.
+func applyHotelLinks(updatedData []supplierHotelsData, currentHotelData map[string]Hotel, links []HotelLink) {
+	canonicalIds := make(map[string]string)
+	for _, link := range links {
+		if link.Status == HotelLinkConfirmed {
+			canonicalIds[hotelLinkKey(link.Supplier, link.SupplierHotelID)] = link.CanonicalID
+		}
+	}
+	if len(canonicalIds) == 0 {
+		return
+	}
+
+	reportedIds := make(map[string]bool)
+	linkedSuppliers := make(map[string]map[string]bool)
+	for i := range updatedData {
+		supplier := updatedData[i].supplier.Name
+		for j := range updatedData[i].hotels {
+			hotel := &updatedData[i].hotels[j]
+			if canonicalId, ok := canonicalIds[hotelLinkKey(supplier, hotel.ID)]; ok && canonicalId != hotel.ID {
+				if linkedSuppliers[hotel.ID] == nil {
+					linkedSuppliers[hotel.ID] = make(map[string]bool)
+				}
+				linkedSuppliers[hotel.ID][supplier] = true
+				hotel.ID = canonicalId
+			}
+			reportedIds[hotel.ID] = true
+		}
+	}
+
+	for id, suppliers := range linkedSuppliers {
+		hotel, stored := currentHotelData[id]
+		if !stored || reportedIds[id] {
+			continue
+		}
+		folded := true
+		if hotel.Provenance != nil {
+			for supplier := range hotel.Provenance.LastSeen {
+				if !suppliers[supplier] {
+					folded = false
+				}
+			}
+		}
+		if folded {
+			delete(currentHotelData, id)
+		}
+	}
+}
+
+// matchWords returns the set of words of a name or an address, ignoring case and punctuation.
+func matchWords(value string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range strings.Fields(locationKey(value)) {
+		words[word] = true
+	}
+	return words
+}
+
+// wordSimilarity is the Dice coefficient of two word sets.
+func wordSimilarity(a map[string]bool, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	common := 0
+	for word := range a {
+		if b[word] {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+func hasCoordinates(location Location) bool {
+	return location.Lat != 0 || location.Long != 0
+}
+
+// distanceMeters is the great-circle distance between two locations.
+func distanceMeters(a Location, b Location) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	deltaLat := lat2 - lat1
+	deltaLong := (b.Long - a.Long) * math.Pi / 180
+	haversine := math.Sin(deltaLat/2)*math.Sin(deltaLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(deltaLong/2)*math.Sin(deltaLong/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(haversine)))
+}
+
+func roundScore(score float64) float64 {
+	return math.Round(score*1000) / 1000
+}
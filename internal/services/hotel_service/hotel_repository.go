@@ -0,0 +1,204 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"ascenda-loyalty-assignment/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// HotelRepository stores the merged hotels, keyed by hotel id.
+type HotelRepository interface {
+	// Get returns the hotel with the given id, the boolean is false when there is none.
+	Get(id string) (Hotel, bool, error)
+	// GetMany returns the hotels with the given ids, unknown ids are skipped.
+	GetMany(ids []string) ([]Hotel, error)
+	FindByDestination(destinationID int) ([]Hotel, error)
+	// Upsert inserts the hotels or replaces the stored hotels with the same id.
+	Upsert(hotels ...Hotel) error
+	Delete(ids ...string) error
+	// Replace makes the repository hold exactly the given hotels, in a single write.
+	Replace(hotels map[string]Hotel) error
+	// Snapshot returns a copy of every stored hotel.
+	Snapshot() (map[string]Hotel, error)
+}
+
+// jsonHotelRepository keeps every hotel in a single JSON object file, the whole file is read on each call.
+// Every successful write is also saved to a backup file, used to recover when the data file is corrupt.
+type jsonHotelRepository struct {
+	mu       sync.Mutex
+	logger   logging.Logger
+	filePath string
+}
+
+const backupFileSuffix = ".bak"
+
+func NewJSONHotelRepository(logger logging.Logger, filePath string) HotelRepository {
+	return &jsonHotelRepository{logger: logger, filePath: filePath}
+}
+
+func (r *jsonHotelRepository) Get(id string) (Hotel, bool, error) {
+	hotels, err := r.Snapshot()
+	if err != nil {
+		return Hotel{}, false, err
+	}
+	hotel, ok := hotels[id]
+	return hotel, ok, nil
+}
+
+func (r *jsonHotelRepository) GetMany(ids []string) ([]Hotel, error) {
+	hotels, err := r.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	found := make([]Hotel, 0, len(ids))
+	for _, id := range ids {
+		if hotel, ok := hotels[id]; ok {
+			found = append(found, hotel)
+		}
+	}
+	return found, nil
+}
+
+func (r *jsonHotelRepository) FindByDestination(destinationID int) ([]Hotel, error) {
+	hotels, err := r.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	found := make([]Hotel, 0)
+	for _, id := range sortedHotelIds(hotels) {
+		if hotels[id].DestinationID == destinationID {
+			found = append(found, hotels[id])
+		}
+	}
+	return found, nil
+}
+
+func (r *jsonHotelRepository) Upsert(hotels ...Hotel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, err := r.readForWrite()
+	if err != nil {
+		return err
+	}
+	for _, hotel := range hotels {
+		stored[hotel.ID] = hotel
+	}
+	return r.write(stored)
+}
+
+func (r *jsonHotelRepository) Delete(ids ...string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, err := r.readForWrite()
+	if err != nil {
+		return err
+	}
+	for _, id := range ids {
+		delete(stored, id)
+	}
+	return r.write(stored)
+}
+
+func (r *jsonHotelRepository) Replace(hotels map[string]Hotel) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.write(hotels)
+}
+
+func (r *jsonHotelRepository) Snapshot() (map[string]Hotel, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.read()
+}
+
+// read returns the hotels of the data file, a missing file is an error. A corrupt data file is restored from
+// the backup file when the backup is valid. So is an empty one, which is what a torn write leaves: the writes
+// store an empty catalog as an empty object, an empty file only holds no hotel when there is no backup.
+func (r *jsonHotelRepository) read() (map[string]Hotel, error) {
+	hotels, err := readHotelsFile(r.filePath)
+	empty := errors.Is(err, errEmptyHotelsFile)
+	if err == nil || !(empty || errors.Is(err, errCorruptHotelsFile)) {
+		return hotels, err
+	}
+
+	backupFilePath := r.filePath + backupFileSuffix
+	backup, backupErr := readHotelsFile(backupFilePath)
+	if backupErr != nil || len(backup) == 0 {
+		if empty {
+			return map[string]Hotel{}, nil
+		}
+		return nil, err
+	}
+	r.logger.Error(fmt.Sprintf("Hotel data file %s is corrupt, restoring it from %s", r.filePath, backupFilePath), err)
+	if err := utils.WriteJSONFile(r.filePath, backup); err != nil {
+		return nil, fmt.Errorf("error restoring hotel data file %s: %w", r.filePath, err)
+	}
+	return backup, nil
+}
+
+// readForWrite is read for the writes, which create a missing data file.
+func (r *jsonHotelRepository) readForWrite() (map[string]Hotel, error) {
+	hotels, err := r.read()
+	if errors.Is(err, os.ErrNotExist) {
+		return map[string]Hotel{}, nil
+	}
+	return hotels, err
+}
+
+func (r *jsonHotelRepository) write(hotels map[string]Hotel) error {
+	err := utils.WriteJSONFile(r.filePath, hotels)
+	if err != nil {
+		return fmt.Errorf("error writing hotel data file %s: %w", r.filePath, err)
+	}
+	err = utils.WriteJSONFile(r.filePath+backupFileSuffix, hotels)
+	if err != nil {
+		r.logger.Warn(fmt.Sprintf("Failed to back up hotel data file %s", r.filePath), err)
+	}
+	return nil
+}
+
+var (
+	errCorruptHotelsFile = errors.New("corrupt hotel data file")
+	errEmptyHotelsFile   = errors.New("empty hotel data file")
+)
+
+func readHotelsFile(filePath string) (map[string]Hotel, error) {
+	isFileEmpty, err := utils.IsFileEmpty(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading hotel data file %s: %w", filePath, err)
+	}
+	if isFileEmpty {
+		return nil, fmt.Errorf("%w %s", errEmptyHotelsFile, filePath)
+	}
+	data, err := utils.ReadJSONFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("error reading hotel data file %s: %w", filePath, err)
+	}
+	var hotels map[string]Hotel
+	err = json.Unmarshal(data, &hotels)
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %v", errCorruptHotelsFile, filePath, err)
+	}
+	if hotels == nil {
+		hotels = map[string]Hotel{}
+	}
+	for id, hotel := range hotels {
+		hotel.ID = id
+		hotels[id] = hotel
+	}
+	return hotels, nil
+}
+
+func sortedHotelIds(hotels map[string]Hotel) []string {
+	ids := make([]string, 0, len(hotels))
+	for id := range hotels {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,159 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHotelRepositories(t *testing.T) {
+	repositories := []struct {
+		description   string
+		newRepository func(t *testing.T) HotelRepository
+	}{
+		{
+			description: "json file",
+			newRepository: func(t *testing.T) HotelRepository {
+				// an empty data file holds no hotel, a missing one is an error
+				filePath := filepath.Join(t.TempDir(), "hotels.json")
+				if err := os.WriteFile(filePath, nil, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return NewJSONHotelRepository(logging.LogrusLogger(), filePath)
+			},
+		},
+		{
+			description: "bolt database",
+			newRepository: func(t *testing.T) HotelRepository {
+				repository, err := NewBoltHotelRepository(filepath.Join(t.TempDir(), "hotels.db"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { repository.Close() })
+				return repository
+			},
+		},
+	}
+
+	beachVillas := Hotel{ID: "iJhz", DestinationID: 5432, HotelName: "Beach Villas Singapore"}
+	interContinental := Hotel{ID: "SjyX", DestinationID: 5432, HotelName: "InterContinental"}
+	hilton := Hotel{ID: "f8c9", DestinationID: 1122, HotelName: "Hilton Tokyo Shinjuku"}
+
+	for _, r := range repositories {
+		t.Run(r.description, func(t *testing.T) {
+			repository := r.newRepository(t)
+
+			hotels, err := repository.Snapshot()
+			assert.Nil(t, err)
+			assert.Empty(t, hotels)
+
+			assert.Nil(t, repository.Upsert(beachVillas, interContinental, hilton))
+			hotel, found, err := repository.Get("iJhz")
+			assert.Nil(t, err)
+			assert.True(t, found)
+			assert.Equal(t, beachVillas, hotel)
+
+			_, found, err = repository.Get("unknown")
+			assert.Nil(t, err)
+			assert.False(t, found)
+
+			many, err := repository.GetMany([]string{"f8c9", "unknown", "SjyX"})
+			assert.Nil(t, err)
+			assert.Equal(t, []Hotel{hilton, interContinental}, many)
+
+			byDestination, err := repository.FindByDestination(5432)
+			assert.Nil(t, err)
+			assert.ElementsMatch(t, []Hotel{beachVillas, interContinental}, byDestination)
+
+			renamed := hilton
+			renamed.HotelName = "Hilton Shinjuku Tokyo"
+			assert.Nil(t, repository.Upsert(renamed))
+			assert.Nil(t, repository.Delete("iJhz", "unknown"))
+
+			hotels, err = repository.Snapshot()
+			assert.Nil(t, err)
+			assert.Equal(t, map[string]Hotel{"SjyX": interContinental, "f8c9": renamed}, hotels)
+
+			assert.Nil(t, repository.Replace(map[string]Hotel{"iJhz": beachVillas, "f8c9": hilton}))
+			hotels, err = repository.Snapshot()
+			assert.Nil(t, err)
+			assert.Equal(t, map[string]Hotel{"iJhz": beachVillas, "f8c9": hilton}, hotels)
+		})
+	}
+}
+
+func TestJSONHotelRepositoryRecovery(t *testing.T) {
+	corruptData, err := os.ReadFile(filepath.Join("test_data", "corrupted_hotels.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hilton := Hotel{ID: "f8c9", DestinationID: 1122, HotelName: "Hilton Tokyo Shinjuku"}
+
+	testCases := []struct {
+		description    string
+		data           []byte
+		withBackup     bool
+		expectedErr    bool
+		expectedHotels map[string]Hotel
+	}{
+		{
+			description:    "restore the data file from the last good backup",
+			data:           corruptData,
+			withBackup:     true,
+			expectedHotels: map[string]Hotel{"f8c9": hilton},
+		},
+		{
+			description: "fail without backup",
+			data:        corruptData,
+			expectedErr: true,
+		},
+		{
+			description:    "restore a truncated data file from the last good backup",
+			data:           []byte{},
+			withBackup:     true,
+			expectedHotels: map[string]Hotel{"f8c9": hilton},
+		},
+		{
+			description:    "read an empty data file without backup as no hotel",
+			data:           []byte{},
+			expectedHotels: map[string]Hotel{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			dir := t.TempDir()
+			filePath := filepath.Join(dir, "hotels.json")
+			repository := NewJSONHotelRepository(logging.LogrusLogger(), filePath)
+			if tc.withBackup {
+				assert.Nil(t, repository.Upsert(hilton))
+			}
+			assert.Nil(t, os.WriteFile(filePath, tc.data, 0644))
+
+			hotels, err := repository.Snapshot()
+			if tc.expectedErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedHotels, hotels)
+			if !tc.withBackup {
+				return
+			}
+
+			restored, err := readHotelsFile(filePath)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.expectedHotels, restored)
+
+			entries, err := os.ReadDir(dir)
+			assert.Nil(t, err)
+			names := make([]string, 0, len(entries))
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+			assert.ElementsMatch(t, []string{"hotels.json", "hotels.json.bak"}, names)
+		})
+	}
+}
@@ -1,16 +1,110 @@
 package hotel_service
 
 type HotelService interface {
-	GetHotels(hotelDataFilePath string, ids []string, destinations []int) ([]Hotel, error)
-	UpdateHotelsFromSuppliers(suppliersFilePath string, hotelDataFilePath string) ([]string, error)
+	GetHotels(ids []string, destinations []int, countries []string, cities []string) ([]Hotel, error)
+	UpdateHotelsFromSuppliers(options UpdateOptions) (UpdateResult, error)
+	ListSnapshots() ([]SnapshotInfo, error)
+	// RestoreSnapshot replaces the hotels with the given version and records the rollback as a new version.
+	RestoreSnapshot(id int) (SnapshotInfo, error)
+	// GetUpdateDiff returns the change recorded by the given update or restore.
+	GetUpdateDiff(id int) (CatalogDiff, error)
+	// GetSupplierCircuits returns the circuit breaker state of the suppliers.
+	GetSupplierCircuits() []SupplierCircuit
+	// GetQuarantine returns the records quarantined by the last update of a supplier, of all suppliers when empty.
+	GetQuarantine(supplier string) ([]QuarantinedRecord, error)
+	// ListHotelLinks returns the links between supplier hotel ids and canonical ids, of all statuses when empty.
+	ListHotelLinks(status string) ([]HotelLink, error)
+	// ConfirmHotelLink makes the next updates store the hotel of the supplier under the canonical id.
+	ConfirmHotelLink(id string) (HotelLink, error)
+	RejectHotelLink(id string) (HotelLink, error)
+}
+
+// UpdateOptions tunes a single UpdateHotelsFromSuppliers run.
+type UpdateOptions struct {
+	// DryRun fetches and merges the supplier data against a copy of the hotels without storing anything.
+	DryRun bool
+	// Progress is called when the fetch of each supplier starts and ends, possibly from several goroutines.
+	Progress func(SupplierProgress)
+}
+
+const (
+	SupplierStatusFetching = "fetching"
+	SupplierStatusFetched  = "fetched"
+	SupplierStatusFailed   = "failed"
+)
+
+// SupplierProgress is the state of the fetch of a single supplier during an update.
+type SupplierProgress struct {
+	Supplier string `json:"supplier"`
+	Status   string `json:"status"`
+	Hotels   int    `json:"hotels,omitempty"`
+	// NotModified is set when the cached payload of the supplier was reused.
+	NotModified bool   `json:"not_modified,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// UpdateResult reports the suppliers an update fetched data from and the supplier images it dropped.
+type UpdateResult struct {
+	Sources []string `json:"sources"`
+	// Suppliers reports the fetch of every enabled supplier, it is also set when the update failed.
+	Suppliers      []SupplierResult `json:"suppliers"`
+	RejectedImages []RejectedImage  `json:"rejected_images,omitempty"`
+	SnapshotID     int              `json:"snapshot_id,omitempty"`
+	// UpdateID identifies the recorded diff, it is only set when the hotels changed.
+	UpdateID int         `json:"update_id,omitempty"`
+	Diff     DiffSummary `json:"diff"`
+	// Changes is the full diff, only set for dry runs as it cannot be fetched later.
+	Changes  *CatalogDiff `json:"changes,omitempty"`
+	Warnings []string     `json:"warnings,omitempty"`
+	DryRun   bool         `json:"dry_run,omitempty"`
+	// Unchanged is set when every supplier answered that its payload did not change, nothing was merged.
+	Unchanged bool `json:"unchanged,omitempty"`
+	// HotelLinksProposed holds the links the matcher proposed, they are only stored for real updates.
+	HotelLinksProposed []HotelLink `json:"hotel_links_proposed,omitempty"`
+}
+
+// Config carries the settings loaded once at startup and shared by every HotelService.
+type Config struct {
+	Repository HotelRepository
+	// Catalog serves the reads and is refreshed after each update, without it every read loads the repository.
+	Catalog           *Catalog
+	SuppliersFilePath string
+	// UpdateLock serializes updates, services sharing a repository must share it. Defaults to a process-wide lock.
+	UpdateLock *UpdateLock
+	// Snapshots keeps a version of the hotels after each update, versioning is disabled without it.
+	Snapshots *SnapshotStore
+	// UpdateDiffs keeps the diff of every update that changed the hotels, diffs are not recorded without it.
+	UpdateDiffs *UpdateDiffStore
+	Mappings    map[string]SupplierMapping
+	Adapters    map[string]SupplierAdapter
+	MergePolicy MergePolicy
+	// DescriptionSimilarityThreshold is the similarity (0-1) above which two descriptions are considered
+	// duplicates, zero uses the default threshold.
+	DescriptionSimilarityThreshold float64
+	ImageValidation                ImageValidation
+	StalenessPolicy                StalenessPolicy
+	RetryPolicy                    RetryPolicy
+	// PayloadCache enables conditional requests to the suppliers, every payload is downloaded without it.
+	PayloadCache *PayloadCache
+	// CircuitBreakers skips the suppliers failing persistently, services must share it. Disabled without it.
+	CircuitBreakers *CircuitBreakers
+	// PartialFailurePolicy aborts updates when required suppliers failed, by default any fetched supplier is enough.
+	PartialFailurePolicy PartialFailurePolicy
+	ValidationRules      ValidationRules
+	// Quarantine keeps the supplier records rejected by the validation for review, they are only logged without it.
+	Quarantine *QuarantineStore
+	// HotelLinks is the table of supplier hotel ids linked to canonical ids, supplier ids are used as is without it.
+	HotelLinks  *HotelLinkStore
+	MatchPolicy MatchPolicy
 }
 
 type Location struct {
-	Lat     float64 `json:"lat,omitempty"`
-	Long    float64 `json:"lng,omitempty"`
-	Address string  `json:"address,omitempty"`
-	City    string  `json:"city,omitempty"`
-	Country string  `json:"country,omitempty"`
+	Lat         float64 `json:"lat,omitempty"`
+	Long        float64 `json:"lng,omitempty"`
+	Address     string  `json:"address,omitempty"`
+	City        string  `json:"city,omitempty"`
+	Country     string  `json:"country,omitempty"`
+	CountryName string  `json:"country_name,omitempty"`
 }
 
 type Amenities struct {
@@ -24,12 +118,17 @@ type Image struct {
 }
 
 type Hotel struct {
-	ID               string             `json:"id,omitempty"`
-	DestinationID    int                `json:"destination_id,omitempty"`
-	HotelName        string             `json:"hotel_name,omitempty"`
-	Location         Location           `json:"location,omitempty"`
-	Description      []string           `json:"description,omitempty"`
-	Amenities        Amenities          `json:"amenities,omitempty"`
-	Images           map[string][]Image `json:"images,omitempty"`
-	BookingCondition []string           `json:"booking_condition,omitempty"`
+	ID            string   `json:"id,omitempty"`
+	DestinationID int      `json:"destination_id,omitempty"`
+	HotelName     string   `json:"hotel_name,omitempty"`
+	Location      Location `json:"location,omitempty"`
+	Description   []string `json:"description,omitempty"`
+	// DescriptionAlternates holds the descriptions not picked when the description strategy is "best".
+	DescriptionAlternates []string           `json:"description_alternates,omitempty"`
+	Amenities             Amenities          `json:"amenities,omitempty"`
+	Images                map[string][]Image `json:"images,omitempty"`
+	BookingCondition      []string           `json:"booking_condition,omitempty"`
+	// Inactive is set by the staleness policy on hotels the suppliers stopped reporting.
+	Inactive   bool             `json:"inactive,omitempty"`
+	Provenance *HotelProvenance `json:"provenance,omitempty"`
 }
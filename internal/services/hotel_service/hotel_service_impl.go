@@ -5,10 +5,13 @@ import (
 	"ascenda-loyalty-assignment/utils"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 )
 
 type HTTPClient interface {
@@ -16,175 +19,505 @@ type HTTPClient interface {
 }
 
 type hotelServiceImpl struct {
-	logger     logging.Logger
-	httpClient HTTPClient
-	ctx        context.Context
+	logger            logging.Logger
+	httpClient        HTTPClient
+	ctx               context.Context
+	repository        HotelRepository
+	catalog           *Catalog
+	updateLock        *UpdateLock
+	snapshots         *SnapshotStore
+	updateDiffs       *UpdateDiffStore
+	suppliersFilePath string
+	mappings          map[string]SupplierMapping
+	adapters          map[string]SupplierAdapter
+	mergePolicy       MergePolicy
+	// descriptionThreshold is the similarity above which two descriptions are considered duplicates.
+	descriptionThreshold float64
+	imageValidation      ImageValidation
+	stalenessPolicy      StalenessPolicy
+	retryPolicy          RetryPolicy
+	circuitBreakers      *CircuitBreakers
+	payloadCache         *PayloadCache
+	partialFailurePolicy PartialFailurePolicy
+	validationRules      ValidationRules
+	quarantine           *QuarantineStore
+	hotelLinks           *HotelLinkStore
+	matchPolicy          MatchPolicy
 }
 
-func NewHotelService(logger logging.Logger, httpClient HTTPClient, ctx context.Context) HotelService {
+// supplierHotelsData holds the normalized hotels returned by a single supplier.
+type supplierHotelsData struct {
+	supplier  Supplier
+	fetchedAt time.Time
+	hotels    []Hotel
+	// warnings describes the records of the supplier that were skipped.
+	warnings []string
+	// quarantined holds the records that failed normalization or validation.
+	quarantined []QuarantinedRecord
+	// notModified is set when the supplier payload did not change since the last applied update.
+	notModified bool
+	// payloadStoredAt identifies the cached payload the hotels come from, zero without payload cache.
+	payloadStoredAt time.Time
+	// incomplete is set when the payload broke off, the hotels read before are kept.
+	incomplete bool
+}
+
+func NewHotelService(logger logging.Logger, httpClient HTTPClient, ctx context.Context, config Config) HotelService {
+	return newHotelServiceImpl(logger, httpClient, ctx, config)
+}
+
+// ValidateSuppliersFile reads and validates the suppliers file of the config against its adapters and mappings,
+// the server calls it at startup so a bad suppliers file fails fast instead of failing every update.
+func ValidateSuppliersFile(logger logging.Logger, config Config) error {
+	_, err := newHotelServiceImpl(logger, nil, context.Background(), config).readSuppliers()
+	return err
+}
+
+func newHotelServiceImpl(logger logging.Logger, httpClient HTTPClient, ctx context.Context, config Config) *hotelServiceImpl {
+	adapters := config.Adapters
+	if adapters == nil {
+		adapters = DefaultSupplierAdapters()
+	}
+	mergePolicy := config.MergePolicy
+	if mergePolicy == nil {
+		mergePolicy = DefaultMergePolicy()
+	}
+	updateLock := config.UpdateLock
+	if updateLock == nil {
+		updateLock = processUpdateLock
+	}
+	descriptionThreshold := config.DescriptionSimilarityThreshold
+	if descriptionThreshold <= 0 {
+		descriptionThreshold = defaultDescriptionSimilarityThreshold
+	}
 	return &hotelServiceImpl{
-		logger:     logger,
-		httpClient: httpClient,
-		ctx:        ctx,
+		logger:               logger,
+		httpClient:           httpClient,
+		ctx:                  ctx,
+		repository:           config.Repository,
+		catalog:              config.Catalog,
+		updateLock:           updateLock,
+		snapshots:            config.Snapshots,
+		updateDiffs:          config.UpdateDiffs,
+		suppliersFilePath:    config.SuppliersFilePath,
+		mappings:             config.Mappings,
+		adapters:             adapters,
+		mergePolicy:          mergePolicy,
+		descriptionThreshold: descriptionThreshold,
+		imageValidation:      config.ImageValidation,
+		stalenessPolicy:      config.StalenessPolicy,
+		retryPolicy:          config.RetryPolicy,
+		circuitBreakers:      config.CircuitBreakers,
+		payloadCache:         config.PayloadCache,
+		partialFailurePolicy: config.PartialFailurePolicy,
+		validationRules:      config.ValidationRules,
+		quarantine:           config.Quarantine,
+		hotelLinks:           config.HotelLinks,
+		matchPolicy:          config.MatchPolicy,
 	}
 }
 
-func (h *hotelServiceImpl) GetHotels(hotelDataFilePath string, ids []string, destinations []int) ([]Hotel, error) {
-	if len(ids) == 0 && len(destinations) == 0 {
+func (h *hotelServiceImpl) GetHotels(ids []string, destinations []int, countries []string, cities []string) ([]Hotel, error) {
+	if len(ids) == 0 && len(destinations) == 0 && len(countries) == 0 && len(cities) == 0 {
 		return []Hotel{}, nil
 	}
 
-	hotels, err := h.getHotelDataFromDataFile(hotelDataFilePath)
-	if err != nil {
-		return []Hotel{}, err
+	catalog := h.catalog
+	if catalog == nil {
+		var err error
+		catalog, err = NewCatalog(h.repository)
+		if err != nil {
+			h.logger.Error("Failed to read hotels from repository", err)
+			return []Hotel{}, fmt.Errorf("failed to get hotel data")
+		}
 	}
 
 	filteredHotels := make([]Hotel, 0)
 	addedHotelIds := make(map[string]bool)
-	h.filterHotelIds(hotels, &filteredHotels, addedHotelIds, ids)
-	h.filterDestinationIds(hotels, &filteredHotels, addedHotelIds, destinations)
+	addFilteredHotels(&filteredHotels, addedHotelIds, catalog.GetMany(ids))
+	for _, destination := range destinations {
+		addFilteredHotels(&filteredHotels, addedHotelIds, catalog.FindByDestination(destination))
+	}
+	for _, country := range countries {
+		addFilteredHotels(&filteredHotels, addedHotelIds, catalog.FindByCountry(country))
+	}
+	for _, city := range cities {
+		addFilteredHotels(&filteredHotels, addedHotelIds, catalog.FindByCity(city))
+	}
 
 	return filteredHotels, nil
 }
 
-func (h *hotelServiceImpl) UpdateHotelsFromSuppliers(suppliersFilePath string, hotelDataFilePath string) ([]string, error) {
-	currentHotelData, err := h.getHotelDataFromDataFile(hotelDataFilePath)
+func (h *hotelServiceImpl) UpdateHotelsFromSuppliers(options UpdateOptions) (UpdateResult, error) {
+	if !options.DryRun {
+		unlock, err := h.updateLock.Lock()
+		if err != nil {
+			h.logger.Error("Failed to acquire the update lock", err)
+			return UpdateResult{}, fmt.Errorf("unable to update new hotel data")
+		}
+		defer unlock()
+	}
+
+	// the repository returns a copy, a dry run can merge into it freely
+	currentHotelData, err := h.repository.Snapshot()
 	if err != nil {
-		return []string{}, err
+		h.logger.Error("Failed to read hotels from repository", err)
+		return UpdateResult{}, fmt.Errorf("failed to get hotel data")
+	}
+	previousHotelData, err := cloneHotels(currentHotelData)
+	if err != nil {
+		h.logger.Error("Failed to copy hotels from repository", err)
+		return UpdateResult{}, fmt.Errorf("failed to get hotel data")
+	}
+
+	// the suppliers file was validated at startup, it is checked again as it may have been edited since
+	suppliers, err := h.readSuppliers()
+	if err != nil {
+		h.logger.Error("Failed to read the suppliers", err)
+		return UpdateResult{}, fmt.Errorf("failed to get suppliers data")
 	}
 
-	isFileEmpty, err := utils.IsFileEmpty(suppliersFilePath)
-	if isFileEmpty || err != nil {
+	suppliers = enabledSuppliers(suppliers)
+	if len(suppliers) == 0 {
+		h.logger.Error("There is no enabled suppliers in data file")
+		return UpdateResult{}, fmt.Errorf("failed to get suppliers data")
+	}
+
+	hotelsDataFromSuppliers, fetchedDataSources, supplierResults, err := h.fetchDataFromSuppliers(suppliers, options.Progress)
+	if err != nil {
+		h.logger.Error("Fail to get data from data sources", err)
+		return UpdateResult{Suppliers: supplierResults, DryRun: options.DryRun}, fmt.Errorf("unable to update new hotel data")
+	}
+	if err := h.partialFailurePolicy.check(suppliers, supplierResults); err != nil {
+		h.logger.Error("Supplier failures break the partial failure policy", err)
+		return UpdateResult{Sources: fetchedDataSources, Suppliers: supplierResults, DryRun: options.DryRun}, err
+	}
+	// an aborted update leaves the quarantine as it was
+	if !options.DryRun {
+		h.quarantineRecords(hotelsDataFromSuppliers)
+	}
+	if allSuppliersNotModified(hotelsDataFromSuppliers) {
+		h.logger.Info("No supplier data changed since the last update, skipping the merge")
+		return UpdateResult{
+			Sources:   fetchedDataSources,
+			Suppliers: supplierResults,
+			Diff:      DiffSummary{},
+			DryRun:    options.DryRun,
+			Unchanged: true,
+		}, nil
+	}
+	var proposedLinks []HotelLink
+	if h.hotelLinks != nil {
+		links, err := h.hotelLinks.List("")
 		if err != nil {
-			h.logger.Error(fmt.Sprintf("Failed to read file %s", suppliersFilePath), err)
-		} else {
-			h.logger.Error("There is no suppliers in data file")
+			h.logger.Error("Fail to read the hotel links", err)
+			return UpdateResult{Sources: fetchedDataSources, Suppliers: supplierResults, DryRun: options.DryRun}, fmt.Errorf("unable to update new hotel data")
 		}
-		return []string{}, fmt.Errorf("failed to get suppliers data")
+		applyHotelLinks(hotelsDataFromSuppliers, currentHotelData, links)
+		proposedLinks = h.matchHotels(hotelsDataFromSuppliers, currentHotelData, links)
+	}
+	rejectedImages := h.sanitizeHotelData(hotelsDataFromSuppliers, currentHotelData)
+	var warnings []string
+	for _, supplierData := range hotelsDataFromSuppliers {
+		warnings = append(warnings, supplierData.warnings...)
+	}
+
+	diff := diffCatalogs(previousHotelData, currentHotelData)
+	result := UpdateResult{
+		Sources:        fetchedDataSources,
+		Suppliers:      supplierResults,
+		RejectedImages: rejectedImages,
+		Diff:           diff.Summary(),
+		Warnings:       warnings,
 	}
-	data, err := utils.ReadJSONFile(suppliersFilePath)
+	if options.DryRun {
+		result.DryRun = true
+		result.Changes = &diff
+		result.HotelLinksProposed = proposedLinks
+		return result, nil
+	}
+
+	if h.snapshots != nil {
+		// the catalog stored before the first update becomes a version, so the first update can be rolled back
+		baseline, saved, err := h.snapshots.SaveBaseline(previousHotelData)
+		if err != nil {
+			h.logger.Error("Fail to save the baseline hotel data snapshot", err)
+		} else if saved {
+			h.logger.Info(fmt.Sprintf("Saved the hotels stored before the first update as snapshot %d", baseline.ID))
+		}
+	}
+	// the staleness policy may have removed hotels
+	err = h.repository.Replace(currentHotelData)
 	if err != nil {
-		h.logger.Error(fmt.Sprintf("Failed to read file %s", suppliersFilePath), err)
-		return []string{}, fmt.Errorf("failed to get suppliers data")
+		h.logger.Error("Fail to write hotels to repository", err)
+		return UpdateResult{Sources: fetchedDataSources, Suppliers: supplierResults}, fmt.Errorf("unable to update new hotel data")
 	}
-	suppliers, err := h.unmarshalSuppliers(data)
+	if h.catalog != nil {
+		h.catalog.replace(currentHotelData)
+	}
+	h.markPayloadsApplied(hotelsDataFromSuppliers)
+	if len(proposedLinks) > 0 {
+		result.HotelLinksProposed, err = h.hotelLinks.propose(proposedLinks)
+		if err != nil {
+			// the hotels are not linked yet, so the matcher proposes them again on the next update
+			h.logger.Error("Fail to store the proposed hotel links", err)
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%d proposed hotel links could not be stored, they are proposed again on the next update", len(proposedLinks)))
+		}
+	}
+
+	result.UpdateID = h.recordUpdateDiff(diff)
+	if h.snapshots != nil {
+		snapshot, err := h.snapshots.Save(currentHotelData, SnapshotInfo{Sources: fetchedDataSources, UpdateID: result.UpdateID})
+		if err != nil {
+			h.logger.Error("Fail to save hotel data snapshot", err)
+		} else {
+			result.SnapshotID = snapshot.ID
+		}
+	}
+
+	return result, nil
+}
+
+func (h *hotelServiceImpl) ListSnapshots() ([]SnapshotInfo, error) {
+	if h.snapshots == nil {
+		return []SnapshotInfo{}, nil
+	}
+	snapshots, err := h.snapshots.List()
 	if err != nil {
-		h.logger.Error("fail to parse json suppliers data", err)
-		return []string{}, fmt.Errorf("failed to get suppliers data")
+		h.logger.Error("Fail to list hotel data snapshots", err)
+		return []SnapshotInfo{}, fmt.Errorf("failed to get snapshots")
 	}
+	return snapshots, nil
+}
 
-	if len(suppliers) == 0 {
-		h.logger.Error("There is no suppliers in data file")
-		return []string{}, fmt.Errorf("failed to get suppliers data")
+func (h *hotelServiceImpl) RestoreSnapshot(id int) (SnapshotInfo, error) {
+	if h.snapshots == nil {
+		return SnapshotInfo{}, fmt.Errorf("%w: %d", ErrSnapshotNotFound, id)
+	}
+	unlock, err := h.updateLock.Lock()
+	if err != nil {
+		h.logger.Error("Failed to acquire the update lock", err)
+		return SnapshotInfo{}, fmt.Errorf("unable to restore snapshot")
 	}
+	defer unlock()
 
-	hotelsDataFromSuppliers, fetchedDataSources, err := h.fetchDataFromSuppliers(suppliers)
+	restored, hotels, err := h.snapshots.Load(id)
 	if err != nil {
-		h.logger.Error("Fail to get data from data sources", err)
-		return []string{}, fmt.Errorf("unable to update new hotel data")
+		if errors.Is(err, ErrSnapshotNotFound) {
+			return SnapshotInfo{}, err
+		}
+		h.logger.Error(fmt.Sprintf("Fail to load hotel data snapshot %d", id), err)
+		return SnapshotInfo{}, fmt.Errorf("unable to restore snapshot")
+	}
+	current, err := h.repository.Snapshot()
+	if err == nil {
+		err = h.repository.Replace(hotels)
+	}
+	if err != nil {
+		h.logger.Error(fmt.Sprintf("Fail to restore hotel data snapshot %d", id), err)
+		return SnapshotInfo{}, fmt.Errorf("unable to restore snapshot")
+	}
+	if h.catalog != nil {
+		h.catalog.replace(hotels)
 	}
-	h.sanitizeHotelData(hotelsDataFromSuppliers, currentHotelData)
 
-	err = utils.WriteJSONFile(hotelDataFilePath, currentHotelData)
+	updateID := h.recordUpdateDiff(diffCatalogs(current, hotels))
+	snapshot, err := h.snapshots.Save(hotels, SnapshotInfo{Sources: restored.Sources, RestoredFrom: id, UpdateID: updateID})
 	if err != nil {
-		h.logger.Error("Fail to write to hotel json data file", err)
-		return []string{}, fmt.Errorf("unable to update new hotel data")
+		h.logger.Error("Fail to save hotel data snapshot", err)
+		return restored, nil
 	}
+	return snapshot, nil
+}
 
-	return fetchedDataSources, nil
+// recordUpdateDiff stores the diff of a change to the catalog and returns its update id, zero when the
+// catalog did not change or the diff could not be stored.
+func (h *hotelServiceImpl) recordUpdateDiff(diff CatalogDiff) int {
+	if h.updateDiffs == nil || diff.Summary() == (DiffSummary{}) {
+		return 0
+	}
+	update, err := h.updateDiffs.Save(diff)
+	if err != nil {
+		h.logger.Error("Fail to save the update diff", err)
+		return 0
+	}
+	return update.ID
 }
 
-func (h *hotelServiceImpl) getHotelDataFromDataFile(hotelDataFilePath string) (map[string]Hotel, error) {
-	isFileEmpty, err := utils.IsFileEmpty(hotelDataFilePath)
-	if isFileEmpty || err != nil {
-		if err != nil {
-			h.logger.Error(fmt.Sprintf("Failed to read file %s", hotelDataFilePath), err)
-			return map[string]Hotel{}, fmt.Errorf("failed to get hotel data")
+func (h *hotelServiceImpl) GetUpdateDiff(id int) (CatalogDiff, error) {
+	if h.updateDiffs == nil {
+		return CatalogDiff{}, fmt.Errorf("%w: %d", ErrUpdateNotFound, id)
+	}
+	update, err := h.updateDiffs.Load(id)
+	if err != nil {
+		if errors.Is(err, ErrUpdateNotFound) {
+			return CatalogDiff{}, err
 		}
-		return map[string]Hotel{}, nil
+		h.logger.Error(fmt.Sprintf("Fail to load the diff of update %d", id), err)
+		return CatalogDiff{}, fmt.Errorf("failed to get update diff")
 	}
+	return update.Diff, nil
+}
 
-	data, err := utils.ReadJSONFile(hotelDataFilePath)
+func (h *hotelServiceImpl) GetSupplierCircuits() []SupplierCircuit {
+	return h.circuitBreakers.States()
+}
+
+func (h *hotelServiceImpl) GetQuarantine(supplier string) ([]QuarantinedRecord, error) {
+	if h.quarantine == nil {
+		return []QuarantinedRecord{}, nil
+	}
+	records, err := h.quarantine.List(supplier)
 	if err != nil {
-		h.logger.Error(fmt.Sprintf("Failed to read file %s", hotelDataFilePath), err)
-		return map[string]Hotel{}, fmt.Errorf("failed to get hotel data")
+		h.logger.Error("Fail to read the quarantined supplier records", err)
+		return []QuarantinedRecord{}, fmt.Errorf("failed to get quarantined records")
 	}
+	return records, nil
+}
 
-	hotels, err := h.unmarshalHotels(data)
+func (h *hotelServiceImpl) ListHotelLinks(status string) ([]HotelLink, error) {
+	if h.hotelLinks == nil {
+		return []HotelLink{}, nil
+	}
+	links, err := h.hotelLinks.List(status)
 	if err != nil {
-		h.logger.Error("fail to parse json hotels data", err)
-		return map[string]Hotel{}, fmt.Errorf("failed to get hotel data")
+		h.logger.Error("Fail to read the hotel links", err)
+		return []HotelLink{}, fmt.Errorf("failed to get hotel links")
 	}
+	return links, nil
+}
 
-	return hotels, nil
+func (h *hotelServiceImpl) ConfirmHotelLink(id string) (HotelLink, error) {
+	link, err := h.decideHotelLink(id, HotelLinkConfirmed)
+	if err != nil {
+		return HotelLink{}, err
+	}
+	// the supplier payload may not change before the next update, it has to be merged again to use the link
+	if h.payloadCache != nil {
+		if err := h.payloadCache.markUnapplied(link.Supplier); err != nil {
+			h.logger.Warn(fmt.Sprintf("Failed to mark the cached payload of supplier %s as unapplied", link.Supplier), err)
+		}
+	}
+	return link, nil
 }
 
-func (h *hotelServiceImpl) filterHotelIds(
-	hotels map[string]Hotel,
-	filteredHotels *[]Hotel,
-	addedHotelIds map[string]bool,
-	ids []string,
-) {
-	if len(ids) == 0 {
-		return
+func (h *hotelServiceImpl) RejectHotelLink(id string) (HotelLink, error) {
+	return h.decideHotelLink(id, HotelLinkRejected)
+}
+
+func (h *hotelServiceImpl) decideHotelLink(id string, status string) (HotelLink, error) {
+	if h.hotelLinks == nil {
+		return HotelLink{}, fmt.Errorf("%w: %s", ErrHotelLinkNotFound, id)
 	}
-	idsMap := map[string]bool{}
-	for _, id := range ids {
-		idsMap[id] = true
+	link, err := h.hotelLinks.decide(id, status)
+	if err != nil {
+		if errors.Is(err, ErrHotelLinkNotFound) || errors.Is(err, ErrHotelLinkConflict) {
+			return HotelLink{}, err
+		}
+		h.logger.Error(fmt.Sprintf("Fail to update hotel link %s", id), err)
+		return HotelLink{}, fmt.Errorf("unable to update hotel link")
 	}
-	for id, hotel := range hotels {
-		if idsMap[id] && !addedHotelIds[id] {
-			hotel.ID = id
+	h.logger.Info(fmt.Sprintf("Hotel link %s of supplier %s is %s", id, link.Supplier, status))
+	return link, nil
+}
+
+func addFilteredHotels(filteredHotels *[]Hotel, addedHotelIds map[string]bool, hotels []Hotel) {
+	for _, hotel := range hotels {
+		if !addedHotelIds[hotel.ID] {
 			*filteredHotels = append(*filteredHotels, hotel)
-			addedHotelIds[id] = true
+			addedHotelIds[hotel.ID] = true
 		}
 	}
 }
 
-func (h *hotelServiceImpl) filterDestinationIds(
-	hotels map[string]Hotel,
-	filteredHotels *[]Hotel,
-	addedHotelIds map[string]bool,
-	destinations []int,
-) {
-	if len(destinations) == 0 {
-		return
+// allSuppliersNotModified tells whether every fetched supplier reused a payload that was already applied.
+func allSuppliersNotModified(suppliersData []supplierHotelsData) bool {
+	if len(suppliersData) == 0 {
+		return false
 	}
-	destinationIdsMap := map[int]bool{}
-	for _, id := range destinations {
-		destinationIdsMap[id] = true
+	for _, supplierData := range suppliersData {
+		if !supplierData.notModified {
+			return false
+		}
 	}
+	return true
+}
 
-	for id, hotel := range hotels {
-		if destinationIdsMap[hotel.DestinationID] && !addedHotelIds[id] {
-			hotel.ID = id
-			*filteredHotels = append(*filteredHotels, hotel)
-			addedHotelIds[id] = true
+// markPayloadsApplied records that the cached supplier payloads were merged into the stored hotels.
+func (h *hotelServiceImpl) markPayloadsApplied(suppliersData []supplierHotelsData) {
+	if h.payloadCache == nil {
+		return
+	}
+	payloads := make(map[string]time.Time)
+	for _, supplierData := range suppliersData {
+		if !supplierData.payloadStoredAt.IsZero() {
+			payloads[supplierData.supplier.Name] = supplierData.payloadStoredAt
 		}
 	}
+	if err := h.payloadCache.markApplied(payloads); err != nil {
+		h.logger.Warn("Unable to mark the cached supplier payloads as applied", err)
+	}
 }
 
-func (h *hotelServiceImpl) unmarshalHotels(data []byte) (map[string]Hotel, error) {
-	var hotels map[string]Hotel
-	err := json.Unmarshal(data, &hotels)
+// quarantineRecords replaces the quarantined records of the fetched suppliers with the ones of this update.
+func (h *hotelServiceImpl) quarantineRecords(suppliersData []supplierHotelsData) {
+	if h.quarantine == nil {
+		return
+	}
+	var suppliers []string
+	var records []QuarantinedRecord
+	for _, supplierData := range suppliersData {
+		suppliers = append(suppliers, supplierData.supplier.Name)
+		records = append(records, supplierData.quarantined...)
+	}
+	if err := h.quarantine.replace(suppliers, records); err != nil {
+		h.logger.Warn("Unable to store the quarantined supplier records", err)
+	}
+}
+
+// readSuppliers reads and validates the suppliers file.
+func (h *hotelServiceImpl) readSuppliers() ([]Supplier, error) {
+	isFileEmpty, err := utils.IsFileEmpty(h.suppliersFilePath)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("failed to read file %s: %w", h.suppliersFilePath, err)
+	}
+	if isFileEmpty {
+		return nil, fmt.Errorf("there is no suppliers in data file %s", h.suppliersFilePath)
+	}
+	data, err := utils.ReadJSONFile(h.suppliersFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file %s: %w", h.suppliersFilePath, err)
 	}
-	return hotels, nil
+	suppliers, err := h.unmarshalSuppliers(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid suppliers data file %s: %w", h.suppliersFilePath, err)
+	}
+	return suppliers, nil
 }
 
-func (h *hotelServiceImpl) unmarshalSuppliers(data []byte) ([]string, error) {
-	var suppliers []string
+func (h *hotelServiceImpl) unmarshalSuppliers(data []byte) ([]Supplier, error) {
+	var suppliers []Supplier
 	err := json.Unmarshal(data, &suppliers)
 	if err != nil {
 		return nil, err
 	}
+	err = h.validateSuppliers(suppliers)
+	if err != nil {
+		return nil, err
+	}
 	return suppliers, nil
 }
 
-func (h *hotelServiceImpl) fetchDataFromSuppliers(suppliers []string) ([]map[string]interface{}, []string, error) {
-	var fetchedHotelsData []map[string]interface{}
+func (h *hotelServiceImpl) fetchDataFromSuppliers(suppliers []Supplier, progress func(SupplierProgress)) ([]supplierHotelsData, []string, []SupplierResult, error) {
+	reportProgress := func(supplierProgress SupplierProgress) {
+		if progress != nil {
+			progress(supplierProgress)
+		}
+	}
+	var fetchedHotelsData []supplierHotelsData
 	var fetchedDataSources []string
+	results := make([]SupplierResult, 0, len(suppliers))
+	var errs []string
 	var mu sync.Mutex
 
 	var wg sync.WaitGroup
@@ -193,415 +526,299 @@ func (h *hotelServiceImpl) fetchDataFromSuppliers(suppliers []string) ([]map[str
 	routineCtx, cancel := context.WithCancel(h.ctx)
 	defer cancel()
 
-	errChan := make(chan error, len(suppliers))
-
-	for _, url := range suppliers {
-		go func(url string) {
+	for _, supplier := range suppliers {
+		go func(supplier Supplier) {
 			defer wg.Done()
+			supplierData, result, err := h.fetchSupplier(routineCtx, supplier, reportProgress)
 
-			req, err := http.NewRequestWithContext(routineCtx, http.MethodGet, url, nil)
-			if err != nil {
-				select {
-				case errChan <- fmt.Errorf("error creating request for %s: %w", url, err):
-				case <-routineCtx.Done():
-					return
-				}
-				return
-			}
-
-			resp, err := h.httpClient.Do(req)
+			mu.Lock()
+			defer mu.Unlock()
+			results = append(results, result)
 			if err != nil {
-				select {
-				case errChan <- fmt.Errorf("error fetching data from %s: %w", url, err):
-				case <-routineCtx.Done():
-					return
-				}
+				errs = append(errs, err.Error())
 				return
 			}
-			defer resp.Body.Close()
-
-			if resp.StatusCode != http.StatusOK {
-				select {
-				case errChan <- fmt.Errorf("supplier %s returned status code: %d", url, resp.StatusCode):
-				case <-routineCtx.Done():
-					return
-				}
-				return
-			}
-
-			var hotels []map[string]interface{}
-			err = json.NewDecoder(resp.Body).Decode(&hotels)
-			if err != nil {
-				select {
-				case errChan <- fmt.Errorf("error decoding JSON from %s: %w", url, err):
-				case <-routineCtx.Done():
-					return
-				}
-				return
-			}
-
-			mu.Lock()
-			fetchedHotelsData = append(fetchedHotelsData, hotels...)
-			fetchedDataSources = append(fetchedDataSources, url)
-			h.logger.Info("Successfully fetching data from supplier ", url)
-			mu.Unlock()
-		}(url)
-	}
-
-	go func() {
-		wg.Wait()
-		close(errChan)
-	}()
-
-	var errs []string
-	for err := range errChan {
-		errs = append(errs, err.Error())
+			fetchedHotelsData = append(fetchedHotelsData, supplierData)
+			fetchedDataSources = append(fetchedDataSources, supplier.Name)
+		}(supplier)
 	}
+	wg.Wait()
 
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Supplier < results[j].Supplier
+	})
 	if len(errs) > 0 {
 		h.logger.Error("Error occurred while fetching data from suppliers", strings.Join(errs, "\n"))
 		if len(errs) >= len(suppliers) {
-			return fetchedHotelsData, fetchedDataSources, fmt.Errorf(strings.Join(errs, "\n"))
+			return fetchedHotelsData, fetchedDataSources, results, errors.New(strings.Join(errs, "\n"))
 		}
 	}
 
-	return fetchedHotelsData, fetchedDataSources, nil
+	return fetchedHotelsData, fetchedDataSources, results, nil
 }
 
-func (h *hotelServiceImpl) sanitizeHotelData(updatedData []map[string]interface{}, currentHotelData map[string]Hotel) {
-	for _, hotel := range updatedData {
-		id := h.getHotelIdFromUpdatedData(hotel)
-		if id == "" {
-			continue
-		}
-		var newHotelData Hotel
-		if _, exists := currentHotelData[id]; !exists {
-			newHotelData = Hotel{
-				ID: id,
-			}
+// fetchSupplier fetches and normalizes the hotels of a single supplier. The result is always set, an error is
+// returned when no hotels could be read.
+func (h *hotelServiceImpl) fetchSupplier(ctx context.Context, supplier Supplier, reportProgress func(SupplierProgress)) (supplierHotelsData, SupplierResult, error) {
+	started := time.Now()
+	result := SupplierResult{Supplier: supplier.Name}
+	fetch := &supplierFetch{}
+	fail := func(err error) (supplierHotelsData, SupplierResult, error) {
+		result.Status = SupplierStatusFailed
+		result.DurationMs = time.Since(started).Milliseconds()
+		result.ErrorCategory = supplierErrorCategory(err, fetch)
+		result.Error = err.Error()
+		reportProgress(SupplierProgress{Supplier: supplier.Name, Status: SupplierStatusFailed, Error: err.Error()})
+		return supplierHotelsData{}, result, err
+	}
+
+	if !h.circuitBreakers.allow(supplier.Name) {
+		err := fmt.Errorf("supplier %s skipped, its %w", supplier.Name, errCircuitOpen)
+		h.logger.Warn(err.Error())
+		return fail(err)
+	}
+
+	reportProgress(SupplierProgress{Supplier: supplier.Name, Status: SupplierStatusFetching})
+	adapter := h.adapterForSupplier(supplier)
+	var client HTTPClient = &retryingClient{client: h.httpClient, policy: h.retryPolicy, logger: h.logger, supplier: supplier.Name, fetch: fetch}
+	if h.payloadCache != nil {
+		client = &conditionalClient{client: client, cache: h.payloadCache, logger: h.logger, supplier: supplier.Name, fetch: fetch}
+	}
+	var hotels []Hotel
+	var warnings []string
+	var quarantined []QuarantinedRecord
+	err := adapter.Fetch(ctx, client, supplier, func(record json.RawMessage) {
+		result.Records++
+		hotel, err := adapter.Normalize(record)
+		var violations []RecordViolation
+		if err != nil {
+			violations = []RecordViolation{{Rule: ValidationRuleInvalidRecord, Message: err.Error()}}
 		} else {
-			newHotelData = currentHotelData[id]
-		}
-		destinationId := h.getDestinationIdFromUpdatedData(hotel)
-		if destinationId == -1 {
-			continue
-		}
-		newHotelData.DestinationID = destinationId
-
-		newHotelData.HotelName = h.getHotelNameFromUpdatedData(hotel)
-
-		newLocation := h.getLocationFromUpdatedData(hotel)
-		if newLocation.Lat != 0.0 {
-			newHotelData.Location.Lat = newLocation.Lat
-		}
-		if newLocation.Long != 0.0 {
-			newHotelData.Location.Long = newLocation.Long
-		}
-		if newLocation.Address != "" {
-			newHotelData.Location.Address = newLocation.Address
-		}
-		if newLocation.City != "" {
-			newHotelData.Location.City = newLocation.City
-		}
-		if newLocation.Country != "" {
-			newHotelData.Location.Country = newLocation.Country
-		}
-
-		newDescription := h.getHotelDescriptionFromUpdatedData(hotel)
-		if newDescription != "" && !utils.SliceContains(newHotelData.Description, newDescription) {
-			newHotelData.Description = append(newHotelData.Description, newDescription)
+			violations = h.validationRules.validate(hotel)
 		}
-
-		newConditions := h.getHotelBookingConditionFromUpdatedData(hotel)
-		for _, condition := range newConditions {
-			if !utils.SliceContains(newHotelData.BookingCondition, condition) {
-				newHotelData.BookingCondition = append(newHotelData.BookingCondition, condition)
-			}
-		}
-
-		newAmenities := h.getHotelAmenitiesFromUpdatedData(hotel)
-		for _, genAmenity := range newAmenities.General {
-			if !utils.SliceContains(newHotelData.Amenities.General, genAmenity) {
-				newHotelData.Amenities.General = append(newHotelData.Amenities.General, genAmenity)
+		if len(violations) > 0 {
+			messages := make([]string, 0, len(violations))
+			for _, violation := range violations {
+				messages = append(messages, violation.Message)
 			}
+			h.logger.Warn(fmt.Sprintf("Quarantining invalid hotel %s from supplier %s", hotel.ID, supplier.Name), messages)
+			warnings = append(warnings, fmt.Sprintf("supplier %s: quarantined invalid hotel %s: %s", supplier.Name, hotel.ID, strings.Join(messages, "; ")))
+			quarantined = append(quarantined, QuarantinedRecord{
+				Supplier:      supplier.Name,
+				HotelID:       hotel.ID,
+				QuarantinedAt: time.Now().UTC(),
+				Violations:    violations,
+				Record:        record,
+			})
+			result.RecordsRejected++
+			return
 		}
-		for _, roomAmenity := range newAmenities.Room {
-			if !utils.SliceContains(newHotelData.Amenities.Room, roomAmenity) {
-				newHotelData.Amenities.Room = append(newHotelData.Amenities.Room, roomAmenity)
+		if dropped := h.validationRules.dropInvalidElements(&hotel); len(dropped) > 0 {
+			messages := make([]string, 0, len(dropped))
+			for _, violation := range dropped {
+				messages = append(messages, violation.Message)
 			}
+			h.logger.Warn(fmt.Sprintf("Dropping invalid values of hotel %s from supplier %s", hotel.ID, supplier.Name), messages)
+			warnings = append(warnings, fmt.Sprintf("supplier %s: dropped invalid values of hotel %s: %s", supplier.Name, hotel.ID, strings.Join(messages, "; ")))
 		}
+		hotels = append(hotels, hotel)
+	})
+	result.HTTPStatus = fetch.statusCode
+	result.Attempts = fetch.attempts
+	incomplete := errors.Is(err, ErrIncompletePayload)
+	if incomplete {
+		h.logger.Warn(fmt.Sprintf("Keeping the %d hotels read from supplier %s", len(hotels), supplier.Name), err)
+		warnings = append(warnings, fmt.Sprintf("supplier %s: %v", supplier.Name, err))
+		result.ErrorCategory = supplierErrorCategory(err, fetch)
+		result.Error = err.Error()
+	} else if err != nil {
+		h.circuitBreakers.recordFailure(supplier.Name)
+		return fail(err)
+	}
+
+	h.circuitBreakers.recordSuccess(supplier.Name)
+	h.logger.Info("Successfully fetching data from supplier ", supplier.Name)
+	result.Status = SupplierStatusFetched
+	result.NotModified = fetch.notModified
+	result.DurationMs = time.Since(started).Milliseconds()
+	reportProgress(SupplierProgress{
+		Supplier:    supplier.Name,
+		Status:      SupplierStatusFetched,
+		Hotels:      len(hotels),
+		NotModified: fetch.notModified,
+	})
+	return supplierHotelsData{
+		supplier:        supplier,
+		fetchedAt:       time.Now().UTC(),
+		hotels:          hotels,
+		warnings:        warnings,
+		quarantined:     quarantined,
+		notModified:     fetch.notModified,
+		payloadStoredAt: fetch.storedAt,
+		incomplete:      incomplete,
+	}, result, nil
+}
 
-		newImages := h.getHotelImagesFromUpdatedData(hotel)
-		for imageCategory, images := range newImages {
-			if newHotelData.Images == nil {
-				newHotelData.Images = make(map[string][]Image)
-			}
-			curImages, exists := newHotelData.Images[imageCategory]
-			if !exists {
-				newHotelData.Images[imageCategory] = images
-				continue
+// sanitizeHotelData normalizes and merges the supplier hotels into currentHotelData,
+// it returns the images rejected by the image validation.
+func (h *hotelServiceImpl) sanitizeHotelData(updatedData []supplierHotelsData, currentHotelData map[string]Hotel) []RejectedImage {
+	sortSupplierHotelsData(updatedData)
+
+	var rejectedImages []RejectedImage
+	var hotelIds []string
+	hotelsById := make(map[string][]sourcedHotel)
+	for _, supplierData := range updatedData {
+		source := FieldSource{Supplier: supplierData.supplier.Name, FetchedAt: supplierData.fetchedAt}
+		for _, hotel := range supplierData.hotels {
+			hotel.Amenities = normalizeAmenities(hotel.Amenities)
+			hotel.Location = normalizeLocation(hotel.Location)
+			var rejected []RejectedImage
+			hotel.Images, rejected = normalizeImages(hotel.Images, h.imageValidation)
+			for _, image := range rejected {
+				image.HotelID = hotel.ID
+				image.Supplier = supplierData.supplier.Name
+				h.logger.Warn(fmt.Sprintf("Rejected image %s of hotel %s from supplier %s: %s", image.Link, image.HotelID, image.Supplier, image.Reason))
+				rejectedImages = append(rejectedImages, image)
 			}
-			for _, image := range images {
-				exist := false
-				for _, curImage := range curImages {
-					if curImage.Link == image.Link {
-						exist = true
-						break
-					}
-				}
-				if !exist {
-					newHotelData.Images[imageCategory] = append(newHotelData.Images[imageCategory], image)
-				}
+			if _, exists := hotelsById[hotel.ID]; !exists {
+				hotelIds = append(hotelIds, hotel.ID)
 			}
+			hotelsById[hotel.ID] = append(hotelsById[hotel.ID], sourcedHotel{hotel: hotel, source: source})
 		}
-
-		currentHotelData[id] = newHotelData
 	}
-}
 
-func (h *hotelServiceImpl) getHotelIdFromUpdatedData(hotel map[string]interface{}) string {
-	idKeys := []string{"id", "Id", "hotel_id"} // add another key here  for future data sources
-	var id interface{}
-
-	for _, idKey := range idKeys {
-		if val, ok := hotel[idKey]; ok {
-			id = val
-			break
-		}
-	}
-	if id == nil {
-		h.logger.Warn("Data is invalid, missing hotelId", hotel)
-		return ""
+	reportedHotelIds := make(map[string]bool, len(hotelIds))
+	for _, id := range hotelIds {
+		h.mergeHotelData(h.mergeSupplierHotels(hotelsById[id]), currentHotelData)
+		reportedHotelIds[id] = true
 	}
-
-	return fmt.Sprintf("%v", id)
-}
-
-func (h *hotelServiceImpl) getDestinationIdFromUpdatedData(hotel map[string]interface{}) int {
-	destinationIdKeys := []string{"destination_id", "DestinationId", "destination"} // add another key here for future data sources
-	var destinationId interface{}
-
-	for _, idKey := range destinationIdKeys {
-		if val, ok := hotel[idKey]; ok {
-			destinationId = val
-			break
+	// a quarantined hotel is still reported by its supplier, it is not stale
+	for _, supplierData := range updatedData {
+		for _, record := range supplierData.quarantined {
+			if record.HotelID != "" {
+				reportedHotelIds[record.HotelID] = true
+			}
 		}
 	}
-	if destinationId == nil {
-		h.logger.Warn("Data is invalid, missing destination id", hotel)
-		return -1
-	}
-
-	destinationIdFloat, ok := destinationId.(float64)
-	if !ok {
-		h.logger.Warn("Data is invalid, destination id is not in integer format", hotel)
-		return -1
-	}
-	if destinationIdFloat != float64(int(destinationIdFloat)) {
-		h.logger.Warn("Data is invalid, destination id is not in integer format", hotel)
-	}
-	return int(destinationIdFloat)
-}
-
-func (h *hotelServiceImpl) getHotelNameFromUpdatedData(hotel map[string]interface{}) string {
-	nameKeys := []string{"Name", "name", "hotel_name"} // add another key here for future data sources
-	var name interface{}
-
-	for _, nameKey := range nameKeys {
-		if val, ok := hotel[nameKey]; ok {
-			name = val
-			break
+	fetchedSuppliers := make(map[string]bool, len(updatedData))
+	for _, supplierData := range updatedData {
+		// hotels missing from an incomplete payload may be in the part that was not read
+		if !supplierData.incomplete {
+			fetchedSuppliers[supplierData.supplier.Name] = true
 		}
 	}
-
-	return utils.ConvertInterfaceToString(name)
+	h.applyStaleness(currentHotelData, reportedHotelIds, fetchedSuppliers)
+	return rejectedImages
 }
 
-func (h *hotelServiceImpl) getLocationFromUpdatedData(hotel map[string]interface{}) Location {
-	locationKeys := []string{"location", "Location"} // add another key here  for future data sources
-	latKeys := []string{"lat", "Latitude"}           // add another key here  for future data sources
-	lngKeys := []string{"Longitude", "lng"}          // add another key here  for future data sources
-	cityKeys := []string{"City", "city"}             // add another key here  for future data sources
-	addressKeys := []string{"Address", "address"}    // add another key here  for future data sources
-	countryKeys := []string{"country", "Country"}    // add another key here  for future data sources
-
-	hotelLocationDataContainer := hotel
-	for _, locKey := range locationKeys {
-		if loc, ok := hotel[locKey]; ok {
-			hotelLocationDataContainer = loc.(map[string]interface{})
-			break
+func (h *hotelServiceImpl) mergeHotelData(hotel Hotel, currentHotelData map[string]Hotel) {
+	var newHotelData Hotel
+	if _, exists := currentHotelData[hotel.ID]; !exists {
+		newHotelData = Hotel{
+			ID: hotel.ID,
 		}
-	}
-
-	var newLocation Location
-
-	for _, latKey := range latKeys {
-		if val, ok := hotelLocationDataContainer[latKey]; ok {
-			if latVal, ok := val.(float64); ok {
-				newLocation.Lat = latVal
-			} else {
-				h.logger.Warn("Latitude data type not supported", val)
-			}
-			break
+	} else {
+		newHotelData = currentHotelData[hotel.ID]
+		normalizeStoredAmenities(&newHotelData)
+		normalizeStoredImages(&newHotelData)
+		newHotelData.Location = normalizeLocation(newHotelData.Location)
+		if h.stalenessPolicy.DropWithdrawnValues {
+			dropWithdrawnValues(&newHotelData, hotel)
 		}
 	}
-
-	for _, lngKey := range lngKeys {
-		if val, ok := hotelLocationDataContainer[lngKey]; ok {
-			if lngVal, ok := val.(float64); ok {
-				newLocation.Long = lngVal
-			} else {
-				h.logger.Warn("Longitude data type not supported", val)
-			}
-			break
-		}
+	newHotelData.Inactive = false
+	if hotel.DestinationID != 0 {
+		newHotelData.DestinationID = hotel.DestinationID
 	}
 
-	for _, addKey := range addressKeys {
-		if address, ok := hotelLocationDataContainer[addKey]; ok {
-			newLocation.Address = utils.ConvertInterfaceToString(address)
-			break
-		}
+	if hotel.HotelName != "" {
+		newHotelData.HotelName = hotel.HotelName
 	}
 
-	for _, cityKey := range cityKeys {
-		if city, ok := hotelLocationDataContainer[cityKey]; ok {
-			newLocation.City = utils.ConvertInterfaceToString(city)
-			break
-		}
+	if hotel.Location.Lat != 0.0 {
+		newHotelData.Location.Lat = hotel.Location.Lat
 	}
-
-	for _, countryKey := range countryKeys {
-		if country, ok := hotelLocationDataContainer[countryKey]; ok {
-			newLocation.Country = utils.ConvertInterfaceToString(country)
-			break
-		}
+	if hotel.Location.Long != 0.0 {
+		newHotelData.Location.Long = hotel.Location.Long
 	}
-
-	return newLocation
-
-}
-
-func (h *hotelServiceImpl) getHotelDescriptionFromUpdatedData(hotel map[string]interface{}) string {
-	descKeys := []string{"description", "Description", "info", "details"} // add another key here  for future data sources
-	var description interface{}
-
-	for _, descKey := range descKeys {
-		if val, ok := hotel[descKey]; ok {
-			description = val
-			break
-		}
+	if hotel.Location.Address != "" {
+		newHotelData.Location.Address = hotel.Location.Address
 	}
-	if description == nil {
-		return ""
+	if hotel.Location.City != "" {
+		newHotelData.Location.City = hotel.Location.City
+	}
+	if hotel.Location.Country != "" {
+		newHotelData.Location.Country = hotel.Location.Country
+		newHotelData.Location.CountryName = hotel.Location.CountryName
 	}
 
-	return utils.ConvertInterfaceToString(description)
-}
+	descriptionStrategy := h.mergePolicy.strategyFor(MergeFieldDescription)
+	storedDescriptions := append(append([]string(nil), newHotelData.Description...), newHotelData.DescriptionAlternates...)
+	mergedDescriptions := append(append([]string(nil), hotel.Description...), hotel.DescriptionAlternates...)
+	newHotelData.Description, newHotelData.DescriptionAlternates = selectDescriptions(
+		mergeStoredList(storedDescriptions, mergedDescriptions, descriptionStrategy), descriptionStrategy, h.descriptionThreshold,
+	)
 
-func (h *hotelServiceImpl) getHotelBookingConditionFromUpdatedData(hotel map[string]interface{}) []string {
-	condKeys := []string{"booking_conditions"} // add another key here  for future data sources
-	var bookingConditions []string
+	amenitiesStrategy := h.mergePolicy.strategyFor(MergeFieldAmenities)
+	newHotelData.BookingCondition = mergeStoredList(newHotelData.BookingCondition, hotel.BookingCondition, h.mergePolicy.strategyFor(MergeFieldBookingConditions))
+	newHotelData.Amenities.General = mergeStoredList(newHotelData.Amenities.General, hotel.Amenities.General, amenitiesStrategy)
+	newHotelData.Amenities.Room = mergeStoredList(newHotelData.Amenities.Room, hotel.Amenities.Room, amenitiesStrategy)
 
-	for _, condKey := range condKeys {
-		if conditions, ok := hotel[condKey]; ok {
-			if conditionsVal, ok := conditions.([]interface{}); ok {
-				for _, conditions := range conditionsVal {
-					bookingConditions = append(bookingConditions, utils.ConvertInterfaceToString(conditions))
-				}
-			} else {
-				h.logger.Warn("Booking Condition data type not supported", conditions)
-			}
-			break
+	if h.mergePolicy.strategyFor(MergeFieldImages) != MergeStrategyUnion {
+		if len(hotel.Images) > 0 {
+			newHotelData.Images = hotel.Images
 		}
-	}
-	return bookingConditions
-}
-
-func (h *hotelServiceImpl) getHotelAmenitiesFromUpdatedData(hotel map[string]interface{}) Amenities {
-	amenityKey := []string{"amenities", "Facilities"} // add another key here  for future data sources
-	var hotelAmenities Amenities
-
-	for _, aKey := range amenityKey {
-		if amenities, ok := hotel[aKey]; ok {
-			if amenitiesVal, ok := amenities.([]interface{}); ok {
-				for _, amenity := range amenitiesVal {
-					hotelAmenities.General = append(hotelAmenities.General, utils.ConvertInterfaceToString(amenity))
-				}
-				continue
-			} else {
-				h.logger.Warn("Amenities data type not supported", amenities)
+	} else {
+		// the stored images take the latest supplier link, a signed link may have changed
+		storedImages := make(map[string]*Image)
+		for _, images := range newHotelData.Images {
+			for i := range images {
+				storedImages[canonicalImageURL(images[i].Link)] = &images[i]
 			}
-			if amenitiesVal, ok := amenities.(map[string]interface{}); ok {
-				if generalAmenities, exists := amenitiesVal["general"]; exists {
-					if listGeneralAmenities, ok := generalAmenities.([]interface{}); ok {
-						for _, amenity := range listGeneralAmenities {
-							hotelAmenities.General = append(hotelAmenities.General, utils.ConvertInterfaceToString(amenity))
-						}
-					} else {
-						h.logger.Warn("Amenities data type not supported", generalAmenities)
-					}
+		}
+		for _, imageCategory := range sortedImageCategories(hotel.Images) {
+			for _, image := range hotel.Images[imageCategory] {
+				key := canonicalImageURL(image.Link)
+				if stored, exists := storedImages[key]; exists {
+					stored.Link = image.Link
+					continue
 				}
-				if roomAmenities, exists := amenitiesVal["room"]; exists {
-					if listRoomAmenities, ok := roomAmenities.([]interface{}); ok {
-						for _, amenity := range listRoomAmenities {
-							hotelAmenities.Room = append(hotelAmenities.Room, utils.ConvertInterfaceToString(amenity))
-						}
-					} else {
-						h.logger.Warn("Amenities data type not supported", roomAmenities)
-					}
+				if newHotelData.Images == nil {
+					newHotelData.Images = make(map[string][]Image)
 				}
-			} else {
-				h.logger.Warn("Amenities data type not supported", amenities)
+				newHotelData.Images[imageCategory] = append(newHotelData.Images[imageCategory], image)
+				storedImages[key] = &Image{}
 			}
-			break
 		}
 	}
 
-	return hotelAmenities
+	newHotelData.Provenance = mergeProvenance(newHotelData.Provenance, hotel.Provenance)
+	newHotelData.Provenance.keepElements(MergeFieldDescription, append(newHotelData.Description, newHotelData.DescriptionAlternates...))
+	newHotelData.Provenance.keepElements(ProvenanceFieldGeneralAmenities, newHotelData.Amenities.General)
+	newHotelData.Provenance.keepElements(ProvenanceFieldRoomAmenities, newHotelData.Amenities.Room)
+	newHotelData.Provenance.keepElements(MergeFieldBookingConditions, newHotelData.BookingCondition)
+	var imageKeys []string
+	for _, link := range imageLinks(newHotelData.Images) {
+		imageKeys = append(imageKeys, canonicalImageURL(link))
+	}
+	newHotelData.Provenance.keepElements(MergeFieldImages, imageKeys)
+
+	currentHotelData[hotel.ID] = newHotelData
 }
 
-func (h *hotelServiceImpl) getHotelImagesFromUpdatedData(hotel map[string]interface{}) map[string][]Image {
-	imageKeys := []string{"images", "Images"}           // add another key here for future data sources
-	imageLinkKeys := []string{"url", "link"}            // add another key here for future data sources
-	imageDescKeys := []string{"caption", "description"} // add another key here for future data sources
-
-	hotelImages := make(map[string][]Image)
-	for _, iKey := range imageKeys {
-		if images, ok := hotel[iKey]; ok {
-			if imagesVal, ok := images.(map[string]interface{}); ok {
-				for imageType, imagesOfType := range imagesVal {
-					hotelImages[imageType] = []Image{}
-					if listImages, ok := imagesOfType.([]interface{}); ok {
-						for _, image := range listImages {
-							if imageMap, ok := image.(map[string]interface{}); ok {
-								newImage := Image{}
-								for _, imageLinkKey := range imageLinkKeys {
-									if link, ok := imageMap[imageLinkKey]; ok {
-										newImage.Link = utils.ConvertInterfaceToString(link)
-										break
-									}
-								}
-								for _, imageDescKey := range imageDescKeys {
-									if desc, ok := imageMap[imageDescKey]; ok {
-										newImage.Description = utils.ConvertInterfaceToString(desc)
-										break
-									}
-								}
-								hotelImages[imageType] = append(hotelImages[imageType], newImage)
-							} else {
-								h.logger.Warn("Images data type not supported", image)
-							}
-						}
-					} else {
-						h.logger.Warn("Images data type not supported", imagesOfType)
-					}
-				}
-			} else {
-				h.logger.Warn("Images data type not supported", images)
-			}
-			break
+// mergeStoredList adds the merged supplier values to the stored ones under the union strategy. Other
+// strategies already picked the values of the suppliers, they replace the stored ones once there are some.
+func mergeStoredList(stored []string, merged []string, strategy MergeStrategy) []string {
+	if strategy != MergeStrategyUnion {
+		if len(merged) > 0 {
+			return append([]string(nil), merged...)
+		}
+		return stored
+	}
+	for _, value := range merged {
+		if !utils.SliceContains(stored, value) {
+			stored = append(stored, value)
 		}
 	}
-	return hotelImages
+	return stored
 }
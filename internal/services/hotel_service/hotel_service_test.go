@@ -30,6 +30,19 @@ func (m *MockHTTPClient) Do(req *http.Request) (*http.Response, error) {
 	}, nil
 }
 
+func copyTestDataFile(t *testing.T, fileName string) string {
+	wd, _ := os.Getwd()
+	data, err := os.ReadFile(filepath.Join(wd, "test_data", fileName))
+	if err != nil {
+		t.Fatal(err)
+	}
+	filePath := filepath.Join(t.TempDir(), fileName)
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	return filePath
+}
+
 func TestGetAllHotels(t *testing.T) {
 	testCases := []struct {
 		description  string
@@ -48,6 +61,16 @@ func TestGetAllHotels(t *testing.T) {
 			},
 			expectedErr: true,
 		},
+		{
+			description:  "fail to get data from corrupted data file",
+			ids:          []string{"iJhz"},
+			destinations: []int{5432},
+			dataFilePath: func() string {
+				wd, _ := os.Getwd()
+				return filepath.Join(wd, "test_data", "corrupted_hotels.json")
+			},
+			expectedErr: true,
+		},
 		{
 			description: "return empty hotel list when not parsing any ids or destinations",
 			dataFilePath: func() string {
@@ -152,8 +175,8 @@ func TestGetAllHotels(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			logger := logging.LogrusLogger()
 			ctx := context.Background()
-			hotelService := NewHotelService(logger, nil, ctx)
-			hotels, err := hotelService.GetHotels(tc.dataFilePath(), tc.ids, tc.destinations)
+			hotelService := NewHotelService(logger, nil, ctx, Config{Repository: NewJSONHotelRepository(logging.LogrusLogger(), tc.dataFilePath())})
+			hotels, err := hotelService.GetHotels(tc.ids, tc.destinations, nil, nil)
 			if tc.expectedErr {
 				assert.NotNil(t, err)
 			} else {
@@ -168,7 +191,7 @@ func TestUpdateHotelsFromSuppliers(t *testing.T) {
 	testCases := []struct {
 		description            string
 		suppliersFilePath      func() string
-		hotelDataFilePath      func() string
+		hotelDataFilePath      func(t *testing.T) string
 		httpClient             func() HTTPClient
 		ids                    []string
 		destinations           []int
@@ -181,7 +204,7 @@ func TestUpdateHotelsFromSuppliers(t *testing.T) {
 			suppliersFilePath: func() string {
 				return "invalid_path"
 			},
-			hotelDataFilePath: func() string {
+			hotelDataFilePath: func(t *testing.T) string {
 				return "valid_path"
 			},
 			httpClient: func() HTTPClient {
@@ -197,7 +220,7 @@ func TestUpdateHotelsFromSuppliers(t *testing.T) {
 				filePath := filepath.Join(wd, "test_data", "invalid_suppliers.json")
 				return filePath
 			},
-			hotelDataFilePath: func() string {
+			hotelDataFilePath: func(t *testing.T) string {
 				return "valid_path"
 			},
 			httpClient: func() HTTPClient {
@@ -213,10 +236,8 @@ func TestUpdateHotelsFromSuppliers(t *testing.T) {
 				filePath := filepath.Join(wd, "test_data", "test_suppliers.json")
 				return filePath
 			},
-			hotelDataFilePath: func() string {
-				wd, _ := os.Getwd()
-				filePath := filepath.Join(wd, "test_data", "test_sanitize_data.json")
-				return filePath
+			hotelDataFilePath: func(t *testing.T) string {
+				return copyTestDataFile(t, "test_sanitize_data.json")
 			},
 			httpClient: func() HTTPClient {
 				mockSupplierData := `[
@@ -346,44 +367,48 @@ func TestUpdateHotelsFromSuppliers(t *testing.T) {
 				return mockClient
 			},
 			expectedErr:            false,
-			expectedFetchedSources: []string{"https://example.com/hotels"},
+			expectedFetchedSources: []string{"example"},
 			expectedData: []Hotel{
 				{
 					ID:            "SjyX",
 					DestinationID: 5432,
 					HotelName:     "InterContinental Singapore Robertson Quay",
 					Location: Location{
-						Address: "1 Nanson Road",
-						City:    "Singapore",
-						Country: "SG",
+						Address:     "1 Nanson Road",
+						City:        "Singapore",
+						Country:     "SG",
+						CountryName: "Singapore",
 					},
 					Description: []string{
 						"Enjoy sophisticated waterfront living at the new InterContinental® Singapore Robertson Quay.",
 					},
 					Amenities: Amenities{
 						General: []string{
-							"Pool", "WiFi", "Aircon", "BusinessCenter", "BathTub", "Breakfast", "DryCleaning", "Bar",
+							"pool", "wifi", "business center", "breakfast", "dry cleaning", "bar",
 						},
+						Room: []string{"aircon", "bathtub"},
 					},
 				},
 				{
 					ID:            "f8c9",
 					DestinationID: 1122,
-					HotelName:     "Hilton Tokyo Shinjuku",
+					HotelName:     "Hilton Shinjuku Tokyo",
 					Location: Location{
-						Lat:     35.6926,
-						Long:    139.690965,
-						Address: "160-0023, SHINJUKU-KU, 6-6-2 NISHI-SHINJUKU, JAPAN",
-						City:    "Tokyo",
-						Country: "JP",
+						Lat:         35.6926,
+						Long:        139.690965,
+						Address:     "160-0023, SHINJUKU-KU, 6-6-2 NISHI-SHINJUKU, JAPAN",
+						City:        "Tokyo",
+						Country:     "JP",
+						CountryName: "Japan",
 					},
 					Description: []string{
 						"Hilton Tokyo is located in Shinjuku, the heart of Tokyo's business, shopping and entertainment district, and is an ideal place to experience modern Japan. A complimentary shuttle operates between the hotel and Shinjuku station and the Tokyo Metro subway is connected to the hotel. Relax in one of the modern Japanese-style rooms and admire stunning city views. The hotel offers WiFi and internet access throughout all rooms and public space.",
 					},
 					Amenities: Amenities{
 						General: []string{
-							"Pool", "WiFi", "BusinessCenter", "DryCleaning", "Breakfast", "Bar", "BathTub",
+							"pool", "wifi", "business center", "dry cleaning", "breakfast", "bar",
 						},
+						Room: []string{"bathtub"},
 					},
 					Images: map[string][]Image{
 						"amenities": {
@@ -400,8 +425,9 @@ func TestUpdateHotelsFromSuppliers(t *testing.T) {
 					DestinationID: 5432,
 					HotelName:     "Beach Villas Singapore",
 					Location: Location{
-						Address: "8 Sentosa Gateway, Beach Villas, 098269",
-						Country: "Singapore",
+						Address:     "8 Sentosa Gateway, Beach Villas, 098269",
+						Country:     "SG",
+						CountryName: "Singapore",
 					},
 					Amenities: Amenities{
 						General: []string{
@@ -434,18 +460,87 @@ func TestUpdateHotelsFromSuppliers(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			logger := logging.LogrusLogger()
 			ctx := context.Background()
-			hotelService := NewHotelService(logger, tc.httpClient(), ctx)
+			repository := NewJSONHotelRepository(logging.LogrusLogger(), tc.hotelDataFilePath(t))
+			config := Config{Repository: repository, SuppliersFilePath: tc.suppliersFilePath()}
+			if catalog, err := NewCatalog(repository); err == nil {
+				config.Catalog = catalog
+			}
+			hotelService := NewHotelService(logger, tc.httpClient(), ctx, config)
 
-			fetchedSources, err := hotelService.UpdateHotelsFromSuppliers(tc.suppliersFilePath(), tc.hotelDataFilePath())
+			result, err := hotelService.UpdateHotelsFromSuppliers(UpdateOptions{})
 			if tc.expectedErr {
 				assert.NotNil(t, err)
 			} else {
 				assert.Nil(t, err)
-				newData, err := hotelService.GetHotels(tc.hotelDataFilePath(), tc.ids, tc.destinations)
+				newData, err := hotelService.GetHotels(tc.ids, tc.destinations, nil, nil)
 				assert.Nil(t, err)
+				for i := range newData {
+					if assert.NotNil(t, newData[i].Provenance) {
+						assert.Equal(t, "example", newData[i].Provenance.Fields[MergeFieldHotelName].Supplier)
+					}
+					newData[i].Provenance = nil
+				}
 				assert.ElementsMatch(t, tc.expectedData, newData)
-				assert.ElementsMatch(t, tc.expectedFetchedSources, fetchedSources)
+				assert.ElementsMatch(t, tc.expectedFetchedSources, result.Sources)
 			}
 		})
 	}
 }
+
+func TestUpdateHotelsFromSuppliersDryRun(t *testing.T) {
+	mockSupplierData := `[
+		{"Id": "SjyX", "DestinationId": 5432, "Name": "InterContinental Robertson Quay", "Country": "SG"},
+		{"Id": "n3w1", "DestinationId": 5432, "Name": "New Hotel", "Country": "SG"},
+		{"Name": "Hotel without id"}
+	]`
+	mockClient := &MockHTTPClient{
+		Responses: map[string]*http.Response{
+			"https://example.com/hotels": {
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(mockSupplierData)),
+			},
+		},
+	}
+
+	hotelDataFilePath := copyTestDataFile(t, "test_sanitize_data.json")
+	originalData, err := os.ReadFile(hotelDataFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := logging.LogrusLogger()
+	repository := NewJSONHotelRepository(logger, hotelDataFilePath)
+	snapshots, err := NewSnapshotStore(filepath.Join(t.TempDir(), "snapshots"), 0)
+	assert.Nil(t, err)
+	wd, _ := os.Getwd()
+	config := Config{
+		Repository:        repository,
+		SuppliersFilePath: filepath.Join(wd, "test_data", "test_suppliers.json"),
+		Snapshots:         snapshots,
+	}
+	hotelService := NewHotelService(logger, mockClient, context.Background(), config)
+
+	var progress []SupplierProgress
+	result, err := hotelService.UpdateHotelsFromSuppliers(UpdateOptions{DryRun: true, Progress: func(supplierProgress SupplierProgress) {
+		progress = append(progress, supplierProgress)
+	}})
+	assert.Nil(t, err)
+	assert.Equal(t, []SupplierProgress{
+		{Supplier: "example", Status: SupplierStatusFetching},
+		{Supplier: "example", Status: SupplierStatusFetched, Hotels: 2},
+	}, progress)
+	assert.True(t, result.DryRun)
+	assert.Equal(t, 0, result.SnapshotID)
+	assert.Equal(t, []string{"example"}, result.Sources)
+	assert.Len(t, result.Warnings, 1)
+	if assert.NotNil(t, result.Changes) {
+		assert.Equal(t, []string{"n3w1"}, result.Changes.Added)
+		assert.Equal(t, 1, result.Diff.HotelsAdded)
+	}
+
+	storedData, err := os.ReadFile(hotelDataFilePath)
+	assert.Nil(t, err)
+	assert.Equal(t, string(originalData), string(storedData))
+	savedSnapshots, err := snapshots.List()
+	assert.Nil(t, err)
+	assert.Empty(t, savedSnapshots)
+}
@@ -0,0 +1,241 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/utils"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+)
+
+const (
+	ImageRejectionInvalidURL           = "invalid_url"
+	ImageRejectionHostNotAllowed       = "host_not_allowed"
+	ImageRejectionUnsupportedExtension = "unsupported_extension"
+)
+
+var defaultImageExtensions = []string{".jpg", ".jpeg", ".png", ".gif", ".webp"}
+
+//go:embed reference_data/image_categories.json
+var imageCategoryData []byte
+
+type imageCategoryEntry struct {
+	Name     string   `json:"name"`
+	Synonyms []string `json:"synonyms"`
+}
+
+// imageCategories indexes the canonical image categories by name and synonym,
+// imageCategoryRanks orders them so an image found in several categories keeps the most specific one.
+var imageCategories, imageCategoryRanks = loadImageCategories(imageCategoryData)
+
+func loadImageCategories(data []byte) (map[string]string, map[string]int) {
+	var entries []imageCategoryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		panic("invalid image categories: " + err.Error())
+	}
+	categories := make(map[string]string)
+	ranks := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		ranks[entry.Name] = i
+		for _, name := range append([]string{entry.Name}, entry.Synonyms...) {
+			categories[locationKey(name)] = entry.Name
+		}
+	}
+	return categories, ranks
+}
+
+// ImageValidation configures the offline checks applied to supplier images, it is disabled by default.
+// AllowedHosts also accepts their subdomains, an empty list allows every host.
+// An empty AllowedExtensions list accepts the usual web image extensions.
+type ImageValidation struct {
+	Enabled           bool     `json:"enabled"`
+	AllowedHosts      []string `json:"allowed_hosts"`
+	AllowedExtensions []string `json:"allowed_extensions"`
+}
+
+// RejectedImage is a supplier image dropped during an update, along with the reason.
+type RejectedImage struct {
+	HotelID  string `json:"hotel_id"`
+	Supplier string `json:"supplier"`
+	Category string `json:"category"`
+	Link     string `json:"link"`
+	Reason   string `json:"reason"`
+}
+
+func LoadImageValidation(filePath string) (ImageValidation, error) {
+	data, err := utils.ReadJSONFile(filePath)
+	if err != nil {
+		return ImageValidation{}, err
+	}
+	var validation ImageValidation
+	err = json.Unmarshal(data, &validation)
+	if err != nil {
+		return ImageValidation{}, fmt.Errorf("invalid image validation file %s: %w", filePath, err)
+	}
+	return validation, nil
+}
+
+// canonicalImageCategory maps a supplier category to its canonical name, unknown categories are lowercased.
+func canonicalImageCategory(category string) string {
+	key := locationKey(category)
+	if canonical, ok := imageCategories[key]; ok {
+		return canonical
+	}
+	return key
+}
+
+func imageCategoryRank(category string) int {
+	if rank, ok := imageCategoryRanks[category]; ok {
+		return rank
+	}
+	return len(imageCategoryRanks)
+}
+
+// sortedImageCategories returns the categories of an image set, the most specific first.
+func sortedImageCategories(images map[string][]Image) []string {
+	categories := make([]string, 0, len(images))
+	for category := range images {
+		categories = append(categories, category)
+	}
+	sortImageCategories(categories)
+	return categories
+}
+
+func sortImageCategories(categories []string) {
+	sort.Slice(categories, func(i, j int) bool {
+		rankI, rankJ := imageCategoryRank(categories[i]), imageCategoryRank(categories[j])
+		if rankI != rankJ {
+			return rankI < rankJ
+		}
+		return categories[i] < categories[j]
+	})
+}
+
+// canonicalImageURL makes links that point to the same image comparable: the scheme becomes https,
+// the host is lowercased without its default port, the path is cleaned and the query and fragment are dropped.
+// Links that cannot be parsed are only trimmed. It is only a key to compare images, the supplier link is stored
+// as is since CDNs may need the query and some hosts only serve http.
+func canonicalImageURL(link string) string {
+	link = strings.TrimSpace(link)
+	parsed, err := url.Parse(link)
+	if err != nil || parsed.Host == "" {
+		return link
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	if scheme != "" && scheme != "http" && scheme != "https" {
+		return link
+	}
+
+	host := strings.ToLower(parsed.Hostname())
+	if port := parsed.Port(); port != "" && port != "80" && port != "443" {
+		host = net.JoinHostPort(host, port)
+	}
+	cleanPath := ""
+	if parsed.Path != "" {
+		cleanPath = path.Clean("/" + parsed.Path)
+	}
+	canonical := url.URL{Scheme: "https", Host: host, Path: cleanPath}
+	return canonical.String()
+}
+
+// rejectionReason runs the offline checks on a supplier link, it returns an empty reason for valid images.
+func (v ImageValidation) rejectionReason(link string) string {
+	parsed, err := url.Parse(link)
+	if err != nil || (!strings.EqualFold(parsed.Scheme, "http") && !strings.EqualFold(parsed.Scheme, "https")) || parsed.Hostname() == "" {
+		return ImageRejectionInvalidURL
+	}
+
+	if len(v.AllowedHosts) > 0 {
+		host := strings.ToLower(parsed.Hostname())
+		allowed := false
+		for _, allowedHost := range v.AllowedHosts {
+			allowedHost = strings.ToLower(strings.TrimSpace(allowedHost))
+			if host == allowedHost || strings.HasSuffix(host, "."+allowedHost) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return ImageRejectionHostNotAllowed
+		}
+	}
+
+	extensions := v.AllowedExtensions
+	if len(extensions) == 0 {
+		extensions = defaultImageExtensions
+	}
+	extension := strings.ToLower(path.Ext(parsed.Path))
+	for _, allowedExtension := range extensions {
+		if extension == strings.ToLower(allowedExtension) {
+			return ""
+		}
+	}
+	return ImageRejectionUnsupportedExtension
+}
+
+// normalizeImages canonicalizes image categories and keeps every image once across categories, in the most
+// specific category. Images are compared by canonical link, their links are only trimmed. Images failing the enabled validation are returned as rejected,
+// without hotel id and supplier.
+func normalizeImages(images map[string][]Image, validation ImageValidation) (map[string][]Image, []RejectedImage) {
+	if len(images) == 0 {
+		return images, nil
+	}
+
+	categories := make(map[string][]string)
+	var canonicalCategories []string
+	for category := range images {
+		canonical := canonicalImageCategory(category)
+		if _, exists := categories[canonical]; !exists {
+			canonicalCategories = append(canonicalCategories, canonical)
+		}
+		categories[canonical] = append(categories[canonical], category)
+	}
+	sortImageCategories(canonicalCategories)
+
+	normalized := make(map[string][]Image)
+	var rejected []RejectedImage
+	seenLinks := make(map[string]bool)
+	for _, canonical := range canonicalCategories {
+		sort.Strings(categories[canonical])
+		for _, category := range categories[canonical] {
+			for _, image := range images[category] {
+				image.Link = strings.TrimSpace(image.Link)
+				key := canonicalImageURL(image.Link)
+				if key == "" || seenLinks[key] {
+					continue
+				}
+				if validation.Enabled {
+					if reason := validation.rejectionReason(image.Link); reason != "" {
+						rejected = append(rejected, RejectedImage{Category: canonical, Link: image.Link, Reason: reason})
+						continue
+					}
+				}
+				seenLinks[key] = true
+				image.Description = strings.TrimSpace(image.Description)
+				normalized[canonical] = append(normalized[canonical], image)
+			}
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, rejected
+	}
+	return normalized, rejected
+}
+
+// normalizeStoredImages normalizes the images of a stored hotel and keys their provenance by canonical link.
+func normalizeStoredImages(hotel *Hotel) {
+	hotel.Images, _ = normalizeImages(hotel.Images, ImageValidation{})
+	if hotel.Provenance == nil || hotel.Provenance.Elements[MergeFieldImages] == nil {
+		return
+	}
+	sources := make(map[string]FieldSource)
+	for link, source := range hotel.Provenance.Elements[MergeFieldImages] {
+		sources[canonicalImageURL(link)] = source
+	}
+	delete(hotel.Provenance.Elements, MergeFieldImages)
+	hotel.Provenance.setElements(MergeFieldImages, sources)
+}
@@ -0,0 +1,162 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCanonicalImageURL(t *testing.T) {
+	testCases := []struct {
+		description  string
+		link         string
+		expectedLink string
+	}{
+		{
+			description:  "drop query string and fragment",
+			link:         "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/1.jpg?w=300#top",
+			expectedLink: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/1.jpg",
+		},
+		{
+			description:  "upgrade scheme and fold host case",
+			link:         " http://D2EY9SQRVKQDFS.cloudfront.net:80/0qZF//1.jpg ",
+			expectedLink: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/1.jpg",
+		},
+		{
+			description:  "scheme relative link",
+			link:         "//d2ey9sqrvkqdfs.cloudfront.net/0qZF/1.jpg",
+			expectedLink: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/1.jpg",
+		},
+		{
+			description:  "keep unparsable links",
+			link:         "not a link",
+			expectedLink: "not a link",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expectedLink, canonicalImageURL(tc.link))
+		})
+	}
+}
+
+func TestNormalizeImages(t *testing.T) {
+	testCases := []struct {
+		description      string
+		images           map[string][]Image
+		validation       ImageValidation
+		expectedImages   map[string][]Image
+		expectedRejected []RejectedImage
+	}{
+		{
+			description: "normalize categories and deduplicate across them",
+			images: map[string][]Image{
+				"Site": {
+					{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/1.jpg", Description: "Front"},
+					{Link: "http://d2ey9sqrvkqdfs.cloudfront.net/0qZF/2.jpg?size=large", Description: "Double room"},
+				},
+				"Bedrooms": {
+					{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/2.jpg", Description: "Double room"},
+				},
+				"facilities": {
+					{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/4.jpg", Description: " Bar "},
+				},
+			},
+			expectedImages: map[string][]Image{
+				"rooms":     {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/2.jpg", Description: "Double room"}},
+				"amenities": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/4.jpg", Description: "Bar"}},
+				"site":      {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/1.jpg", Description: "Front"}},
+			},
+		},
+		{
+			description: "keep the supplier link of signed and plain http images",
+			images: map[string][]Image{
+				"rooms": {
+					{Link: " https://cdn.example.com/0qZF/2.jpg?sig=abc&w=200 ", Description: "Double room"},
+					{Link: "https://cdn.example.com/0qZF/2.jpg?sig=def&w=400", Description: "Double room"},
+					{Link: "http://legacy.example.com/0qZF/3.jpg", Description: "Bathroom"},
+				},
+			},
+			validation: ImageValidation{Enabled: true},
+			expectedImages: map[string][]Image{
+				"rooms": {
+					{Link: "https://cdn.example.com/0qZF/2.jpg?sig=abc&w=200", Description: "Double room"},
+					{Link: "http://legacy.example.com/0qZF/3.jpg", Description: "Bathroom"},
+				},
+			},
+		},
+		{
+			description: "reject images failing validation",
+			images: map[string][]Image{
+				"rooms": {
+					{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/2.jpg", Description: "Double room"},
+					{Link: "https://images.example.com/0qZF/3.jpg", Description: "Double room"},
+					{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/3.pdf", Description: "Floor plan"},
+					{Link: "ftp://d2ey9sqrvkqdfs.cloudfront.net/0qZF/5.jpg", Description: "Bathroom"},
+				},
+			},
+			validation: ImageValidation{Enabled: true, AllowedHosts: []string{"cloudfront.net"}},
+			expectedImages: map[string][]Image{
+				"rooms": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/2.jpg", Description: "Double room"}},
+			},
+			expectedRejected: []RejectedImage{
+				{Category: "rooms", Link: "https://images.example.com/0qZF/3.jpg", Reason: ImageRejectionHostNotAllowed},
+				{Category: "rooms", Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/3.pdf", Reason: ImageRejectionUnsupportedExtension},
+				{Category: "rooms", Link: "ftp://d2ey9sqrvkqdfs.cloudfront.net/0qZF/5.jpg", Reason: ImageRejectionInvalidURL},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			images, rejected := normalizeImages(tc.images, tc.validation)
+			assert.Equal(t, tc.expectedImages, images)
+			assert.Equal(t, tc.expectedRejected, rejected)
+		})
+	}
+}
+
+func TestSanitizeHotelDataImages(t *testing.T) {
+	updatedData := []supplierHotelsData{
+		{
+			supplier: Supplier{Name: "acme", Priority: 2},
+			hotels: []Hotel{{ID: "iJhz", Images: map[string][]Image{
+				"site": {
+					{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/1.jpg", Description: "Front"},
+					{Link: "https://example.com/0qZF/9.jpg", Description: "Pool"},
+				},
+			}}},
+		},
+		{
+			supplier: Supplier{Name: "paperflies", Priority: 1},
+			hotels: []Hotel{{ID: "iJhz", Images: map[string][]Image{
+				"rooms":     {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/2.jpg?v=2", Description: "Double room"}},
+				"amenities": {{Link: "http://d2ey9sqrvkqdfs.cloudfront.net/0qZF/1.jpg", Description: "Entrance"}},
+			}}},
+		},
+	}
+	currentHotelData := map[string]Hotel{
+		"iJhz": {ID: "iJhz", Images: map[string][]Image{
+			"Rooms": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/2.jpg", Description: "Double room"}},
+		}},
+	}
+
+	hotelService := &hotelServiceImpl{
+		logger:          logging.LogrusLogger(),
+		ctx:             context.Background(),
+		mergePolicy:     DefaultMergePolicy(),
+		imageValidation: ImageValidation{Enabled: true, AllowedHosts: []string{"d2ey9sqrvkqdfs.cloudfront.net"}},
+	}
+	rejected := hotelService.sanitizeHotelData(updatedData, currentHotelData)
+
+	assert.Equal(t, []RejectedImage{{
+		HotelID: "iJhz", Supplier: "acme", Category: "site", Link: "https://example.com/0qZF/9.jpg", Reason: ImageRejectionHostNotAllowed,
+	}}, rejected)
+	// the stored image takes the latest supplier link
+	assert.Equal(t, map[string][]Image{
+		"rooms": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/2.jpg?v=2", Description: "Double room"}},
+		"site":  {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/0qZF/1.jpg", Description: "Front"}},
+	}, currentHotelData["iJhz"].Images)
+}
@@ -0,0 +1,125 @@
+package hotel_service
+
+import (
+	_ "embed"
+	"encoding/json"
+	"strings"
+	"unicode"
+)
+
+//go:embed reference_data/countries.json
+var countryTableData []byte
+
+//go:embed reference_data/cities.json
+var cityTableData []byte
+
+// Country is an entry of the embedded ISO 3166-1 table.
+type Country struct {
+	Code    string   `json:"code"`
+	Alpha3  string   `json:"alpha3"`
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+type cityEntry struct {
+	Name    string   `json:"name"`
+	Aliases []string `json:"aliases"`
+}
+
+var (
+	countriesByCode, countriesByKey = loadCountryTable(countryTableData)
+	citiesByKey                     = loadCityTable(cityTableData)
+)
+
+func loadCountryTable(data []byte) (map[string]Country, map[string]Country) {
+	var countries []Country
+	if err := json.Unmarshal(data, &countries); err != nil {
+		panic("invalid country table: " + err.Error())
+	}
+	byCode := make(map[string]Country, len(countries))
+	byKey := make(map[string]Country)
+	for _, country := range countries {
+		byCode[country.Code] = country
+		for _, name := range append([]string{country.Code, country.Alpha3, country.Name}, country.Aliases...) {
+			byKey[locationKey(name)] = country
+		}
+	}
+	return byCode, byKey
+}
+
+func loadCityTable(data []byte) map[string]string {
+	var cities []cityEntry
+	if err := json.Unmarshal(data, &cities); err != nil {
+		panic("invalid city table: " + err.Error())
+	}
+	byKey := make(map[string]string)
+	for _, city := range cities {
+		for _, name := range append([]string{city.Name}, city.Aliases...) {
+			byKey[locationKey(name)] = city.Name
+		}
+	}
+	return byKey
+}
+
+// locationKey folds case, drops punctuation and collapses whitespace so that "U.S.A." matches "usa".
+func locationKey(value string) string {
+	var builder strings.Builder
+	for _, r := range strings.ToLower(value) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsSpace(r):
+			builder.WriteRune(r)
+		case r == '-' || r == '_':
+			builder.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// LookupCountry resolves an ISO alpha-2/alpha-3 code, a country name or a known alias.
+func LookupCountry(value string) (Country, bool) {
+	country, ok := countriesByKey[locationKey(value)]
+	return country, ok
+}
+
+// normalizeCountryCode returns the ISO alpha-2 code of a country, unknown countries are kept as given.
+func normalizeCountryCode(value string) string {
+	value = strings.TrimSpace(value)
+	if country, ok := LookupCountry(value); ok {
+		return country.Code
+	}
+	return value
+}
+
+func countryDisplayName(code string) string {
+	if country, ok := countriesByCode[code]; ok {
+		return country.Name
+	}
+	return ""
+}
+
+// normalizeCity resolves known city aliases, other cities get their words capitalized.
+func normalizeCity(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if value == "" {
+		return ""
+	}
+	if city, ok := citiesByKey[locationKey(value)]; ok {
+		return city
+	}
+
+	words := strings.Fields(strings.ToLower(value))
+	for i, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		words[i] = string(runes)
+	}
+	return strings.Join(words, " ")
+}
+
+// normalizeLocation stores the country as an ISO alpha-2 code with its display name and canonicalizes the city.
+func normalizeLocation(location Location) Location {
+	location.Country = normalizeCountryCode(location.Country)
+	location.CountryName = countryDisplayName(location.Country)
+	location.City = normalizeCity(location.City)
+	return location
+}
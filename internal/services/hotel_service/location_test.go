@@ -0,0 +1,90 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeLocation(t *testing.T) {
+	testCases := []struct {
+		description      string
+		location         Location
+		expectedLocation Location
+	}{
+		{
+			description:      "resolve alpha-2 code",
+			location:         Location{City: "singapore", Country: "SG"},
+			expectedLocation: Location{City: "Singapore", Country: "SG", CountryName: "Singapore"},
+		},
+		{
+			description:      "resolve country name and city casing",
+			location:         Location{City: "  TOKYO ", Country: "Japan"},
+			expectedLocation: Location{City: "Tokyo", Country: "JP", CountryName: "Japan"},
+		},
+		{
+			description:      "resolve country and city aliases",
+			location:         Location{City: "Saigon", Country: "Vietnam"},
+			expectedLocation: Location{City: "Ho Chi Minh City", Country: "VN", CountryName: "Vietnam"},
+		},
+		{
+			description:      "resolve alpha-3 code and punctuated alias",
+			location:         Location{City: "new york city", Country: "U.S.A."},
+			expectedLocation: Location{City: "New York", Country: "US", CountryName: "United States"},
+		},
+		{
+			description:      "keep unknown country and capitalize unknown city",
+			location:         Location{City: "little rock", Country: "Atlantis"},
+			expectedLocation: Location{City: "Little Rock", Country: "Atlantis"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expectedLocation, normalizeLocation(tc.location))
+		})
+	}
+}
+
+func TestGetHotelsByCountry(t *testing.T) {
+	testCases := []struct {
+		description string
+		countries   []string
+		expectedIds []string
+	}{
+		{
+			description: "filter by alpha-2 code",
+			countries:   []string{"jp"},
+			expectedIds: []string{"f8c9"},
+		},
+		{
+			description: "filter by country name and alpha-3 code",
+			countries:   []string{"Singapore", "SGP"},
+			expectedIds: []string{"SjyX", "iJhz"},
+		},
+		{
+			description: "return nothing for unknown country",
+			countries:   []string{"FR"},
+			expectedIds: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			logger := logging.LogrusLogger()
+			wd, _ := os.Getwd()
+			config := Config{Repository: NewJSONHotelRepository(logging.LogrusLogger(), filepath.Join(wd, "test_data", "test_hotels.json"))}
+			hotelService := NewHotelService(logger, nil, context.Background(), config)
+			hotels, err := hotelService.GetHotels(nil, nil, tc.countries, nil)
+			assert.Nil(t, err)
+			ids := make([]string, 0, len(hotels))
+			for _, hotel := range hotels {
+				ids = append(ids, hotel.ID)
+			}
+			assert.ElementsMatch(t, tc.expectedIds, ids)
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/utils"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+type MergeStrategy string
+
+const (
+	// MergeStrategyPriority keeps the value of the highest-priority supplier that has one.
+	MergeStrategyPriority MergeStrategy = "priority"
+	// MergeStrategyLongest keeps the longest string, ties go to the highest-priority supplier.
+	MergeStrategyLongest MergeStrategy = "longest"
+	// MergeStrategyMostFrequent keeps the value reported by most suppliers, ties go to the highest-priority supplier.
+	MergeStrategyMostFrequent MergeStrategy = "most_frequent"
+	// MergeStrategyUnion keeps every distinct list element, ordered by supplier priority.
+	MergeStrategyUnion MergeStrategy = "union"
+	// MergeStrategyBest keeps the longest of the distinct descriptions and the others as alternates.
+	MergeStrategyBest MergeStrategy = "best"
+)
+
+const (
+	MergeFieldDestinationID     = "destination_id"
+	MergeFieldHotelName         = "hotel_name"
+	MergeFieldLat               = "location.lat"
+	MergeFieldLng               = "location.lng"
+	MergeFieldAddress           = "location.address"
+	MergeFieldCity              = "location.city"
+	MergeFieldCountry           = "location.country"
+	MergeFieldDescription       = "description"
+	MergeFieldAmenities         = "amenities"
+	MergeFieldImages            = "images"
+	MergeFieldBookingConditions = "booking_conditions"
+)
+
+// MergePolicy maps a hotel field to the strategy used to merge the values of several suppliers.
+type MergePolicy map[string]MergeStrategy
+
+var mergeFieldStrategies = map[string][]MergeStrategy{
+	MergeFieldDestinationID:     {MergeStrategyPriority, MergeStrategyMostFrequent},
+	MergeFieldHotelName:         {MergeStrategyPriority, MergeStrategyLongest, MergeStrategyMostFrequent},
+	MergeFieldLat:               {MergeStrategyPriority, MergeStrategyMostFrequent},
+	MergeFieldLng:               {MergeStrategyPriority, MergeStrategyMostFrequent},
+	MergeFieldAddress:           {MergeStrategyPriority, MergeStrategyLongest, MergeStrategyMostFrequent},
+	MergeFieldCity:              {MergeStrategyPriority, MergeStrategyLongest, MergeStrategyMostFrequent},
+	MergeFieldCountry:           {MergeStrategyPriority, MergeStrategyLongest, MergeStrategyMostFrequent},
+	MergeFieldDescription:       {MergeStrategyPriority, MergeStrategyUnion, MergeStrategyBest},
+	MergeFieldAmenities:         {MergeStrategyPriority, MergeStrategyUnion},
+	MergeFieldImages:            {MergeStrategyPriority, MergeStrategyUnion},
+	MergeFieldBookingConditions: {MergeStrategyPriority, MergeStrategyUnion},
+}
+
+func DefaultMergePolicy() MergePolicy {
+	return MergePolicy{
+		MergeFieldDestinationID:     MergeStrategyPriority,
+		MergeFieldHotelName:         MergeStrategyPriority,
+		MergeFieldLat:               MergeStrategyPriority,
+		MergeFieldLng:               MergeStrategyPriority,
+		MergeFieldAddress:           MergeStrategyPriority,
+		MergeFieldCity:              MergeStrategyPriority,
+		MergeFieldCountry:           MergeStrategyPriority,
+		MergeFieldDescription:       MergeStrategyUnion,
+		MergeFieldAmenities:         MergeStrategyUnion,
+		MergeFieldImages:            MergeStrategyUnion,
+		MergeFieldBookingConditions: MergeStrategyUnion,
+	}
+}
+
+// LoadMergePolicy reads a field -> strategy JSON object, fields that are not listed keep their default strategy.
+func LoadMergePolicy(filePath string) (MergePolicy, error) {
+	data, err := utils.ReadJSONFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	var overrides MergePolicy
+	err = json.Unmarshal(data, &overrides)
+	if err != nil {
+		return nil, fmt.Errorf("invalid merge policy file %s: %w", filePath, err)
+	}
+
+	policy := DefaultMergePolicy()
+	for field, strategy := range overrides {
+		if !isMergeStrategyAllowed(field, strategy) {
+			return nil, fmt.Errorf("merge strategy %q is not supported for field %q", strategy, field)
+		}
+		policy[field] = strategy
+	}
+	return policy, nil
+}
+
+func isMergeStrategyAllowed(field string, strategy MergeStrategy) bool {
+	strategies, ok := mergeFieldStrategies[field]
+	if !ok {
+		return false
+	}
+	for _, allowed := range strategies {
+		if allowed == strategy {
+			return true
+		}
+	}
+	return false
+}
+
+func (p MergePolicy) strategyFor(field string) MergeStrategy {
+	if strategy, ok := p[field]; ok {
+		return strategy
+	}
+	return DefaultMergePolicy()[field]
+}
+
+// sortSupplierHotelsData orders the fetched data by supplier priority (highest first) then by supplier name,
+// so merging never depends on the order the suppliers responded in.
+func sortSupplierHotelsData(updatedData []supplierHotelsData) {
+	sort.SliceStable(updatedData, func(i, j int) bool {
+		if updatedData[i].supplier.Priority != updatedData[j].supplier.Priority {
+			return updatedData[i].supplier.Priority > updatedData[j].supplier.Priority
+		}
+		return updatedData[i].supplier.Name < updatedData[j].supplier.Name
+	})
+}
+
+// sourcedHotel is a normalized hotel along with the supplier it came from.
+type sourcedHotel struct {
+	hotel  Hotel
+	source FieldSource
+}
+
+// mergeSupplierHotels merges the hotels reported for the same id, ordered by supplier priority.
+func (h *hotelServiceImpl) mergeSupplierHotels(hotels []sourcedHotel) Hotel {
+	var (
+		sources           []FieldSource
+		destinationIds    []int
+		names             []string
+		lats              []float64
+		lngs              []float64
+		addresses         []string
+		cities            []string
+		countries         []string
+		descriptions      [][]string
+		generalAmenities  [][]string
+		roomAmenities     [][]string
+		images            []map[string][]Image
+		bookingConditions [][]string
+	)
+	for _, sourced := range hotels {
+		hotel := sourced.hotel
+		sources = append(sources, sourced.source)
+		destinationIds = append(destinationIds, hotel.DestinationID)
+		names = append(names, hotel.HotelName)
+		lats = append(lats, hotel.Location.Lat)
+		lngs = append(lngs, hotel.Location.Long)
+		addresses = append(addresses, hotel.Location.Address)
+		cities = append(cities, hotel.Location.City)
+		countries = append(countries, hotel.Location.Country)
+		descriptions = append(descriptions, hotel.Description)
+		generalAmenities = append(generalAmenities, hotel.Amenities.General)
+		roomAmenities = append(roomAmenities, hotel.Amenities.Room)
+		images = append(images, hotel.Images)
+		bookingConditions = append(bookingConditions, hotel.BookingCondition)
+	}
+
+	policy := h.mergePolicy
+	provenance := newHotelProvenance()
+	merged := Hotel{
+		ID:            hotels[0].hotel.ID,
+		DestinationID: pickField(provenance, MergeFieldDestinationID, destinationIds, sources, policy, nil),
+		HotelName:     pickField(provenance, MergeFieldHotelName, names, sources, policy, stringLength),
+		Location: Location{
+			Lat:     pickField(provenance, MergeFieldLat, lats, sources, policy, nil),
+			Long:    pickField(provenance, MergeFieldLng, lngs, sources, policy, nil),
+			Address: pickField(provenance, MergeFieldAddress, addresses, sources, policy, stringLength),
+			City:    pickField(provenance, MergeFieldCity, cities, sources, policy, stringLength),
+			Country: pickField(provenance, MergeFieldCountry, countries, sources, policy, stringLength),
+		},
+		Provenance: provenance,
+	}
+	merged.Location.CountryName = countryDisplayName(merged.Location.Country)
+	for _, source := range sources {
+		provenance.LastSeen[source.Supplier] = source.FetchedAt
+	}
+
+	var elementSources map[string]FieldSource
+	merged.Description, merged.DescriptionAlternates, elementSources = mergeDescriptions(
+		descriptions, sources, policy.strategyFor(MergeFieldDescription), h.descriptionThreshold,
+	)
+	provenance.setElements(MergeFieldDescription, elementSources)
+	merged.Amenities.General, elementSources = mergeStringLists(generalAmenities, sources, policy.strategyFor(MergeFieldAmenities))
+	provenance.setElements(ProvenanceFieldGeneralAmenities, elementSources)
+	merged.Amenities.Room, elementSources = mergeStringLists(roomAmenities, sources, policy.strategyFor(MergeFieldAmenities))
+	provenance.setElements(ProvenanceFieldRoomAmenities, elementSources)
+	merged.Images, elementSources = mergeImages(images, sources, policy.strategyFor(MergeFieldImages))
+	provenance.setElements(MergeFieldImages, elementSources)
+	merged.BookingCondition, elementSources = mergeStringLists(bookingConditions, sources, policy.strategyFor(MergeFieldBookingConditions))
+	provenance.setElements(MergeFieldBookingConditions, elementSources)
+
+	return merged
+}
+
+func stringLength(value string) int {
+	return len([]rune(value))
+}
+
+// pickField picks the value of a scalar field and records which supplier provided it.
+func pickField[T comparable](provenance *HotelProvenance, field string, values []T, sources []FieldSource, policy MergePolicy, length func(T) int) T {
+	index := pickValue(values, policy.strategyFor(field), length)
+	if index < 0 {
+		var zero T
+		return zero
+	}
+	provenance.Fields[field] = sources[index]
+	return values[index]
+}
+
+// pickValue returns the index of the selected non-zero value, or -1 when every value is zero.
+// Values are ordered by supplier priority. length is only used by MergeStrategyLongest,
+// without it the strategy falls back to priority.
+func pickValue[T comparable](values []T, strategy MergeStrategy, length func(T) int) int {
+	var zero T
+	picked := -1
+	switch {
+	case strategy == MergeStrategyLongest && length != nil:
+		for i, value := range values {
+			if value != zero && (picked < 0 || length(value) > length(values[picked])) {
+				picked = i
+			}
+		}
+	case strategy == MergeStrategyMostFrequent:
+		counts := make(map[T]int)
+		for _, value := range values {
+			if value != zero {
+				counts[value]++
+			}
+		}
+		for i, value := range values {
+			if value != zero && (picked < 0 || counts[value] > counts[values[picked]]) {
+				picked = i
+			}
+		}
+	default:
+		for i, value := range values {
+			if value != zero {
+				return i
+			}
+		}
+	}
+	return picked
+}
+
+func mergeStringLists(lists [][]string, sources []FieldSource, strategy MergeStrategy) ([]string, map[string]FieldSource) {
+	var merged []string
+	elementSources := make(map[string]FieldSource)
+	for i, list := range lists {
+		if strategy == MergeStrategyPriority && len(merged) > 0 {
+			break
+		}
+		for _, value := range list {
+			if value != "" && !utils.SliceContains(merged, value) {
+				merged = append(merged, value)
+				elementSources[value] = sources[i]
+			}
+		}
+	}
+	return merged, elementSources
+}
+
+// mergeImages merges the image sets ordered by supplier priority, an image is kept once across categories.
+// The element sources are keyed by canonical link.
+func mergeImages(imageSets []map[string][]Image, sources []FieldSource, strategy MergeStrategy) (map[string][]Image, map[string]FieldSource) {
+	var merged map[string][]Image
+	elementSources := make(map[string]FieldSource)
+	for i, imageSet := range imageSets {
+		if strategy == MergeStrategyPriority && len(merged) > 0 {
+			break
+		}
+		for _, category := range sortedImageCategories(imageSet) {
+			for _, image := range imageSet[category] {
+				key := canonicalImageURL(image.Link)
+				if _, exists := elementSources[key]; exists {
+					continue
+				}
+				if merged == nil {
+					merged = make(map[string][]Image)
+				}
+				merged[category] = append(merged[category], image)
+				elementSources[key] = sources[i]
+			}
+		}
+	}
+	return merged, elementSources
+}
@@ -0,0 +1,257 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSanitizeHotelDataMergeStrategies(t *testing.T) {
+	acme := supplierHotelsData{
+		supplier: Supplier{Name: "acme", Priority: 1},
+		hotels: []Hotel{{
+			ID: "SjyX", DestinationID: 5432, HotelName: "InterContinental Singapore Robertson Quay",
+			Location:  Location{Address: "1 Nanson Road", City: "Singapore", Country: "SG"},
+			Amenities: Amenities{General: []string{"Pool", "WiFi"}},
+		}},
+	}
+	patagonia := supplierHotelsData{
+		supplier: Supplier{Name: "patagonia", Priority: 2},
+		hotels: []Hotel{{
+			ID: "SjyX", DestinationID: 5432, HotelName: "InterContinental",
+			Location:  Location{Lat: 1.28967, Long: 103.837, Address: "1 Nanson Rd"},
+			Amenities: Amenities{General: []string{"Aircon", "WiFi"}},
+		}},
+	}
+	paperflies := supplierHotelsData{
+		supplier: Supplier{Name: "paperflies", Priority: 3},
+		hotels: []Hotel{{
+			ID: "SjyX", DestinationID: 5432, HotelName: "InterContinental",
+			Location:  Location{Address: "1 Nanson Rd, Singapore 238909", Country: "Singapore"},
+			Amenities: Amenities{Room: []string{"minibar"}},
+			Images: map[string][]Image{
+				"site": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i1_m.jpg", Description: "Restaurant"}},
+			},
+		}},
+	}
+
+	testCases := []struct {
+		description   string
+		mergePolicy   MergePolicy
+		updatedData   []supplierHotelsData
+		expectedHotel Hotel
+	}{
+		{
+			description: "highest priority supplier wins regardless of response order",
+			mergePolicy: DefaultMergePolicy(),
+			updatedData: []supplierHotelsData{acme, paperflies, patagonia},
+			expectedHotel: Hotel{
+				ID: "SjyX", DestinationID: 5432, HotelName: "InterContinental",
+				Location: Location{
+					Lat: 1.28967, Long: 103.837, Address: "1 Nanson Rd, Singapore 238909", City: "Singapore", Country: "SG", CountryName: "Singapore",
+				},
+				Amenities: Amenities{General: []string{"wifi", "pool"}, Room: []string{"minibar", "aircon"}},
+				Images: map[string][]Image{
+					"site": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i1_m.jpg", Description: "Restaurant"}},
+				},
+			},
+		},
+		{
+			description: "longest and most frequent strategies",
+			mergePolicy: MergePolicy{
+				MergeFieldHotelName: MergeStrategyLongest,
+				MergeFieldAddress:   MergeStrategyMostFrequent,
+				MergeFieldCountry:   MergeStrategyLongest,
+				MergeFieldAmenities: MergeStrategyPriority,
+			},
+			updatedData: []supplierHotelsData{patagonia, acme, paperflies},
+			expectedHotel: Hotel{
+				ID: "SjyX", DestinationID: 5432, HotelName: "InterContinental Singapore Robertson Quay",
+				Location: Location{
+					Lat: 1.28967, Long: 103.837, Address: "1 Nanson Rd, Singapore 238909", City: "Singapore", Country: "SG", CountryName: "Singapore",
+				},
+				Amenities: Amenities{General: []string{"wifi"}, Room: []string{"minibar"}},
+				Images: map[string][]Image{
+					"site": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i1_m.jpg", Description: "Restaurant"}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			logger := logging.LogrusLogger()
+			hotelService := &hotelServiceImpl{logger: logger, ctx: context.Background(), mergePolicy: tc.mergePolicy}
+			currentHotelData := map[string]Hotel{}
+			hotelService.sanitizeHotelData(tc.updatedData, currentHotelData)
+			for id, hotel := range currentHotelData {
+				hotel.Provenance = nil
+				currentHotelData[id] = hotel
+			}
+			assert.Equal(t, map[string]Hotel{"SjyX": tc.expectedHotel}, currentHotelData)
+		})
+	}
+}
+
+func TestSanitizeHotelDataMergeStrategiesIntoStoredHotel(t *testing.T) {
+	low := supplierHotelsData{
+		supplier: Supplier{Name: "low", Priority: 1},
+		hotels: []Hotel{{
+			ID: "SjyX", DestinationID: 5432, HotelName: "InterContinental",
+			Amenities:        Amenities{General: []string{"Spa", "Pool"}},
+			BookingCondition: []string{"low cond"},
+			Images: map[string][]Image{
+				"site": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/low.jpg", Description: "Lobby"}},
+			},
+		}},
+	}
+	high := supplierHotelsData{
+		supplier: Supplier{Name: "high", Priority: 2},
+		hotels: []Hotel{{
+			ID: "SjyX", DestinationID: 5432, HotelName: "InterContinental",
+			Amenities:        Amenities{General: []string{"Pool"}},
+			BookingCondition: []string{"high cond"},
+			Images: map[string][]Image{
+				"site": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/high.jpg", Description: "Front"}},
+			},
+		}},
+	}
+
+	testCases := []struct {
+		description        string
+		mergePolicy        MergePolicy
+		expectedAmenities  Amenities
+		expectedConditions []string
+		expectedImages     map[string][]Image
+	}{
+		{
+			description:        "union keeps the stored values",
+			mergePolicy:        DefaultMergePolicy(),
+			expectedAmenities:  Amenities{General: []string{"spa", "pool"}},
+			expectedConditions: []string{"low cond", "high cond"},
+			expectedImages: map[string][]Image{"site": {
+				{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/low.jpg", Description: "Lobby"},
+				{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/high.jpg", Description: "Front"},
+			}},
+		},
+		{
+			description: "priority replaces the stored values",
+			mergePolicy: MergePolicy{
+				MergeFieldAmenities:         MergeStrategyPriority,
+				MergeFieldBookingConditions: MergeStrategyPriority,
+				MergeFieldImages:            MergeStrategyPriority,
+			},
+			expectedAmenities:  Amenities{General: []string{"pool"}},
+			expectedConditions: []string{"high cond"},
+			expectedImages: map[string][]Image{"site": {
+				{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/high.jpg", Description: "Front"},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			hotelService := &hotelServiceImpl{logger: logging.LogrusLogger(), ctx: context.Background(), mergePolicy: tc.mergePolicy}
+			currentHotelData := map[string]Hotel{}
+			hotelService.sanitizeHotelData([]supplierHotelsData{low}, currentHotelData)
+			hotelService.sanitizeHotelData([]supplierHotelsData{low, high}, currentHotelData)
+
+			hotel := currentHotelData["SjyX"]
+			assert.Equal(t, tc.expectedAmenities, hotel.Amenities)
+			assert.Equal(t, tc.expectedConditions, hotel.BookingCondition)
+			assert.Equal(t, tc.expectedImages, hotel.Images)
+		})
+	}
+}
+
+func TestSanitizeHotelDataChangedDescriptionIntoStoredHotel(t *testing.T) {
+	supplierData := func(description string) supplierHotelsData {
+		return supplierHotelsData{
+			supplier: Supplier{Name: "acme", Priority: 1},
+			hotels:   []Hotel{{ID: "SjyX", DestinationID: 5432, Description: []string{description}}},
+		}
+	}
+	oldDescription := "Old description of the hotel by the river."
+	newDescription := "Completely new text about rooms, pool and breakfast."
+
+	testCases := []struct {
+		description          string
+		mergePolicy          MergePolicy
+		expectedDescriptions []string
+	}{
+		{
+			description:          "union keeps the stored description",
+			mergePolicy:          DefaultMergePolicy(),
+			expectedDescriptions: []string{oldDescription, newDescription},
+		},
+		{
+			description:          "priority replaces the stored description",
+			mergePolicy:          MergePolicy{MergeFieldDescription: MergeStrategyPriority},
+			expectedDescriptions: []string{newDescription},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			hotelService := &hotelServiceImpl{
+				logger:               logging.LogrusLogger(),
+				ctx:                  context.Background(),
+				mergePolicy:          tc.mergePolicy,
+				descriptionThreshold: defaultDescriptionSimilarityThreshold,
+			}
+			currentHotelData := map[string]Hotel{}
+			hotelService.sanitizeHotelData([]supplierHotelsData{supplierData(oldDescription)}, currentHotelData)
+			hotelService.sanitizeHotelData([]supplierHotelsData{supplierData(newDescription)}, currentHotelData)
+
+			hotel := currentHotelData["SjyX"]
+			assert.ElementsMatch(t, tc.expectedDescriptions, append(hotel.Description, hotel.DescriptionAlternates...))
+		})
+	}
+}
+
+func TestLoadMergePolicy(t *testing.T) {
+	testCases := []struct {
+		description    string
+		policyFilePath func() string
+		expectedErr    bool
+	}{
+		{
+			description: "fail to read missing policy file",
+			policyFilePath: func() string {
+				return "invalid_path"
+			},
+			expectedErr: true,
+		},
+		{
+			description: "reject strategy not supported by field",
+			policyFilePath: func() string {
+				wd, _ := os.Getwd()
+				return filepath.Join(wd, "test_data", "invalid_merge_policy.json")
+			},
+			expectedErr: true,
+		},
+		{
+			description: "successfully load the bundled merge policy",
+			policyFilePath: func() string {
+				wd, _ := os.Getwd()
+				return filepath.Join(wd, "..", "..", "data", "merge_policy.json")
+			},
+			expectedErr: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			policy, err := LoadMergePolicy(tc.policyFilePath())
+			if tc.expectedErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Len(t, policy, len(mergeFieldStrategies))
+			}
+		})
+	}
+}
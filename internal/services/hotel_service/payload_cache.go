@@ -0,0 +1,244 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"ascenda-loyalty-assignment/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	payloadCacheInfoExtension = ".json"
+	payloadCacheDataExtension = ".payload"
+)
+
+// cachedPayloadInfo holds the validators of the cached payload of a supplier. Applied is set once an update
+// stored the hotels merged from this payload, only then a 304 response means the supplier data is unchanged.
+type cachedPayloadInfo struct {
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+	Applied      bool      `json:"applied"`
+}
+
+// PayloadCache keeps the last raw payload of every supplier next to its ETag and Last-Modified validators,
+// so unchanged payloads are not downloaded again.
+type PayloadCache struct {
+	mu      sync.Mutex
+	dirPath string
+}
+
+func NewPayloadCache(dirPath string) (*PayloadCache, error) {
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return nil, fmt.Errorf("error creating supplier cache directory %s: %w", dirPath, err)
+	}
+	return &PayloadCache{dirPath: dirPath}, nil
+}
+
+func (c *PayloadCache) filePath(supplier string, extension string) string {
+	return filepath.Join(c.dirPath, url.PathEscape(supplier)+extension)
+}
+
+// info returns the validators of the cached payload of a supplier, if both the payload and its info exist.
+func (c *PayloadCache) info(supplier string) (cachedPayloadInfo, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, err := os.ReadFile(c.filePath(supplier, payloadCacheInfoExtension))
+	if errors.Is(err, os.ErrNotExist) {
+		return cachedPayloadInfo{}, false, nil
+	}
+	if err != nil {
+		return cachedPayloadInfo{}, false, err
+	}
+	var info cachedPayloadInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return cachedPayloadInfo{}, false, fmt.Errorf("invalid supplier cache info of %s: %w", supplier, err)
+	}
+	if _, err := os.Stat(c.filePath(supplier, payloadCacheDataExtension)); err != nil {
+		return cachedPayloadInfo{}, false, nil
+	}
+	return info, true, nil
+}
+
+func (c *PayloadCache) open(supplier string) (*os.File, error) {
+	return os.Open(c.filePath(supplier, payloadCacheDataExtension))
+}
+
+// store moves a downloaded payload into the cache, the payload is not applied yet.
+func (c *PayloadCache) store(supplier string, info cachedPayloadInfo, payloadPath string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	info.Applied = false
+	if err := os.Rename(payloadPath, c.filePath(supplier, payloadCacheDataExtension)); err != nil {
+		return err
+	}
+	return utils.WriteJSONFile(c.filePath(supplier, payloadCacheInfoExtension), info)
+}
+
+// markApplied marks the given payloads, keyed by supplier with their storage time, as applied.
+// A payload stored again since then is left as is.
+func (c *PayloadCache) markApplied(payloads map[string]time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for supplier, storedAt := range payloads {
+		infoPath := c.filePath(supplier, payloadCacheInfoExtension)
+		data, err := os.ReadFile(infoPath)
+		if err != nil {
+			return err
+		}
+		var info cachedPayloadInfo
+		if err := json.Unmarshal(data, &info); err != nil {
+			return err
+		}
+		if !info.StoredAt.Equal(storedAt) || info.Applied {
+			continue
+		}
+		info.Applied = true
+		if err := utils.WriteJSONFile(infoPath, info); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// markUnapplied makes the next update merge the cached payload of a supplier again, even if it did not change.
+func (c *PayloadCache) markUnapplied(supplier string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	infoPath := c.filePath(supplier, payloadCacheInfoExtension)
+	data, err := os.ReadFile(infoPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var info cachedPayloadInfo
+	if err := json.Unmarshal(data, &info); err != nil {
+		return err
+	}
+	if !info.Applied {
+		return nil
+	}
+	info.Applied = false
+	return utils.WriteJSONFile(infoPath, info)
+}
+
+// conditionalClient is an HTTPClient sending the cached validators of a supplier. A 304 response is replaced
+// by the cached payload, a 200 response carrying validators is cached once its body has been read to the end.
+type conditionalClient struct {
+	client   HTTPClient
+	cache    *PayloadCache
+	logger   logging.Logger
+	supplier string
+	fetch    *supplierFetch
+}
+
+func (c *conditionalClient) Do(req *http.Request) (*http.Response, error) {
+	info, cached, err := c.cache.info(c.supplier)
+	if err != nil {
+		c.logger.Warn(fmt.Sprintf("Ignoring the cached payload of supplier %s", c.supplier), err)
+		cached = false
+	}
+	if cached {
+		if info.ETag != "" {
+			req.Header.Set("If-None-Match", info.ETag)
+		}
+		if info.LastModified != "" {
+			req.Header.Set("If-Modified-Since", info.LastModified)
+		}
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return resp, err
+	}
+
+	if resp.StatusCode == http.StatusNotModified && cached {
+		payload, err := c.cache.open(c.supplier)
+		if err != nil {
+			resp.Body.Close()
+			return nil, fmt.Errorf("error reading the cached payload of %s: %w", c.supplier, err)
+		}
+		resp.Body.Close()
+		c.logger.Info(fmt.Sprintf("Supplier %s payload not modified, using the cached payload", c.supplier))
+		c.fetch.notModified = info.Applied
+		c.fetch.storedAt = info.StoredAt
+		return &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Header:     resp.Header,
+			Body:       payload,
+			Request:    req,
+		}, nil
+	}
+
+	etag, lastModified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	if resp.StatusCode != http.StatusOK || (etag == "" && lastModified == "") {
+		return resp, nil
+	}
+	tempFile, err := os.CreateTemp(c.cache.dirPath, "."+url.PathEscape(c.supplier)+".tmp-*")
+	if err != nil {
+		c.logger.Warn(fmt.Sprintf("Unable to cache the payload of supplier %s", c.supplier), err)
+		return resp, nil
+	}
+	resp.Body = &cachingBody{
+		body:     resp.Body,
+		tempFile: tempFile,
+		commit: func(payloadPath string) error {
+			storedAt := time.Now().UTC()
+			err := c.cache.store(c.supplier, cachedPayloadInfo{ETag: etag, LastModified: lastModified, StoredAt: storedAt}, payloadPath)
+			if err == nil {
+				c.fetch.storedAt = storedAt
+			}
+			return err
+		},
+		logger: c.logger,
+	}
+	return resp, nil
+}
+
+// cachingBody copies a response body into a temporary file and commits the file once the body was read
+// to the end, a body closed early is discarded.
+type cachingBody struct {
+	body     io.ReadCloser
+	tempFile *os.File
+	commit   func(payloadPath string) error
+	logger   logging.Logger
+	complete bool
+	failed   bool
+}
+
+func (b *cachingBody) Read(p []byte) (int, error) {
+	n, err := b.body.Read(p)
+	if n > 0 && !b.failed {
+		if _, writeErr := b.tempFile.Write(p[:n]); writeErr != nil {
+			b.failed = true
+		}
+	}
+	if err == io.EOF {
+		b.complete = true
+	}
+	return n, err
+}
+
+func (b *cachingBody) Close() error {
+	err := b.body.Close()
+	tempPath := b.tempFile.Name()
+	closeErr := b.tempFile.Close()
+	if b.complete && !b.failed && closeErr == nil {
+		if commitErr := b.commit(tempPath); commitErr != nil {
+			b.logger.Warn("Unable to cache supplier payload", commitErr)
+		}
+	}
+	os.Remove(tempPath)
+	return err
+}
@@ -0,0 +1,122 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ConditionalHTTPClient serves a payload with an ETag and answers 304 when the request carries that ETag.
+type ConditionalHTTPClient struct {
+	Payload  string
+	ETag     string
+	Requests []*http.Request
+}
+
+func (m *ConditionalHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	m.Requests = append(m.Requests, req)
+	if req.Header.Get("If-None-Match") == m.ETag {
+		return &http.Response{
+			StatusCode: http.StatusNotModified,
+			Header:     http.Header{"Etag": {m.ETag}},
+			Body:       io.NopCloser(strings.NewReader("")),
+		}, nil
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Etag": {m.ETag}},
+		Body:       io.NopCloser(strings.NewReader(m.Payload)),
+	}, nil
+}
+
+func TestConditionalClient(t *testing.T) {
+	logger := logging.LogrusLogger()
+	cache, err := NewPayloadCache(t.TempDir())
+	assert.Nil(t, err)
+	mockClient := &ConditionalHTTPClient{Payload: `[{"Id": "SjyX"}]`, ETag: `"v1"`}
+	supplier := Supplier{Name: "example", URL: "https://example.com/hotels"}
+	fetchPayload := func() ([]byte, *supplierFetch) {
+		fetch := &supplierFetch{}
+		client := &conditionalClient{client: mockClient, cache: cache, logger: logger, supplier: supplier.Name, fetch: fetch}
+		var records []json.RawMessage
+		err := httpFetcher{}.Fetch(context.Background(), client, supplier, func(record json.RawMessage) {
+			records = append(records, record)
+		})
+		assert.Nil(t, err)
+		assert.Len(t, records, 1)
+		return records[0], fetch
+	}
+
+	record, fetch := fetchPayload()
+	assert.JSONEq(t, `{"Id": "SjyX"}`, string(record))
+	assert.False(t, fetch.notModified)
+	assert.False(t, fetch.storedAt.IsZero(), "the payload is cached")
+	assert.Empty(t, mockClient.Requests[0].Header.Get("If-None-Match"))
+
+	record, fetch = fetchPayload()
+	assert.JSONEq(t, `{"Id": "SjyX"}`, string(record), "the cached payload replaces the 304 response")
+	assert.False(t, fetch.notModified, "the cached payload was not applied yet")
+	assert.Equal(t, `"v1"`, mockClient.Requests[1].Header.Get("If-None-Match"))
+
+	assert.Nil(t, cache.markApplied(map[string]time.Time{supplier.Name: fetch.storedAt}))
+	_, fetch = fetchPayload()
+	assert.True(t, fetch.notModified)
+
+	mockClient.Payload, mockClient.ETag = `[{"Id": "f8c9"}]`, `"v2"`
+	record, fetch = fetchPayload()
+	assert.JSONEq(t, `{"Id": "f8c9"}`, string(record))
+	assert.False(t, fetch.notModified)
+	info, cached, err := cache.info(supplier.Name)
+	assert.Nil(t, err)
+	assert.True(t, cached)
+	assert.Equal(t, `"v2"`, info.ETag)
+	assert.False(t, info.Applied)
+}
+
+func TestUpdateHotelsFromSuppliersNotModified(t *testing.T) {
+	logger := logging.LogrusLogger()
+	cache, err := NewPayloadCache(filepath.Join(t.TempDir(), "supplier_cache"))
+	assert.Nil(t, err)
+	snapshots, err := NewSnapshotStore(filepath.Join(t.TempDir(), "snapshots"), 0)
+	assert.Nil(t, err)
+	wd, _ := os.Getwd()
+	config := Config{
+		Repository:        NewJSONHotelRepository(logger, copyTestDataFile(t, "test_sanitize_data.json")),
+		SuppliersFilePath: filepath.Join(wd, "test_data", "test_suppliers.json"),
+		Snapshots:         snapshots,
+		PayloadCache:      cache,
+	}
+	mockClient := &ConditionalHTTPClient{
+		Payload: `[{"Id": "n3w1", "DestinationId": 5432, "Name": "New Hotel", "Country": "SG"}]`,
+		ETag:    `"v1"`,
+	}
+	hotelService := NewHotelService(logger, mockClient, context.Background(), config)
+
+	dryRun, err := hotelService.UpdateHotelsFromSuppliers(UpdateOptions{DryRun: true})
+	assert.Nil(t, err)
+	assert.False(t, dryRun.Unchanged)
+
+	first, err := hotelService.UpdateHotelsFromSuppliers(UpdateOptions{})
+	assert.Nil(t, err)
+	assert.False(t, first.Unchanged, "a payload cached by a dry run is merged by the next update")
+	assert.Equal(t, 1, first.Diff.HotelsAdded)
+	assert.NotZero(t, first.SnapshotID)
+
+	second, err := hotelService.UpdateHotelsFromSuppliers(UpdateOptions{})
+	assert.Nil(t, err)
+	assert.True(t, second.Unchanged)
+	assert.Equal(t, []string{"example"}, second.Sources)
+	assert.Zero(t, second.SnapshotID)
+	// the baseline and the first update, the unchanged update saved no version
+	savedSnapshots, err := snapshots.List()
+	assert.Nil(t, err)
+	assert.Len(t, savedSnapshots, 2)
+}
@@ -0,0 +1,90 @@
+package hotel_service
+
+import "time"
+
+const (
+	ProvenanceFieldGeneralAmenities = "amenities.general"
+	ProvenanceFieldRoomAmenities    = "amenities.room"
+)
+
+// FieldSource tells which supplier provided a value and when it was fetched.
+type FieldSource struct {
+	Supplier  string    `json:"supplier"`
+	FetchedAt time.Time `json:"fetched_at"`
+}
+
+// HotelProvenance records the source of every merged value.
+// Fields is keyed by field name (e.g. "hotel_name", "location.lat"), Elements is keyed by list field name
+// (e.g. "description", "amenities.general", "images") then by element value, images are keyed by link.
+// LastSeen is the last fetch time of each supplier that reported the hotel, MissedUpdates counts the
+// updates since then that fetched one of those suppliers without finding the hotel.
+type HotelProvenance struct {
+	Fields        map[string]FieldSource            `json:"fields,omitempty"`
+	Elements      map[string]map[string]FieldSource `json:"elements,omitempty"`
+	LastSeen      map[string]time.Time              `json:"last_seen,omitempty"`
+	MissedUpdates int                               `json:"missed_updates,omitempty"`
+}
+
+func newHotelProvenance() *HotelProvenance {
+	return &HotelProvenance{
+		Fields:   make(map[string]FieldSource),
+		Elements: make(map[string]map[string]FieldSource),
+		LastSeen: make(map[string]time.Time),
+	}
+}
+
+func (p *HotelProvenance) setElements(field string, sources map[string]FieldSource) {
+	if len(sources) == 0 {
+		return
+	}
+	if p.Elements[field] == nil {
+		p.Elements[field] = make(map[string]FieldSource)
+	}
+	for element, source := range sources {
+		p.Elements[field][element] = source
+	}
+}
+
+// mergeProvenance overlays the provenance of a freshly merged hotel onto the stored one,
+// values that were not provided again keep their previous source. The hotel was just reported,
+// so its missed updates are reset.
+func mergeProvenance(current *HotelProvenance, updated *HotelProvenance) *HotelProvenance {
+	if updated == nil {
+		return current
+	}
+	merged := newHotelProvenance()
+	for _, provenance := range []*HotelProvenance{current, updated} {
+		if provenance == nil {
+			continue
+		}
+		for field, source := range provenance.Fields {
+			merged.Fields[field] = source
+		}
+		for field, sources := range provenance.Elements {
+			merged.setElements(field, sources)
+		}
+		for supplier, seenAt := range provenance.LastSeen {
+			merged.LastSeen[supplier] = seenAt
+		}
+	}
+	return merged
+}
+
+// keepElements drops the sources of the elements of a list field that are no longer in the list.
+func (p *HotelProvenance) keepElements(field string, elements []string) {
+	if p == nil || p.Elements[field] == nil {
+		return
+	}
+	kept := make(map[string]bool, len(elements))
+	for _, element := range elements {
+		kept[element] = true
+	}
+	for element := range p.Elements[field] {
+		if !kept[element] {
+			delete(p.Elements[field], element)
+		}
+	}
+	if len(p.Elements[field]) == 0 {
+		delete(p.Elements, field)
+	}
+}
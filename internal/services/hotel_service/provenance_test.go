@@ -0,0 +1,73 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSanitizeHotelDataProvenance(t *testing.T) {
+	acmeFetchedAt := time.Date(2024, 11, 1, 8, 0, 0, 0, time.UTC)
+	paperfliesFetchedAt := time.Date(2024, 11, 1, 8, 0, 5, 0, time.UTC)
+	previousFetchedAt := time.Date(2024, 10, 1, 8, 0, 0, 0, time.UTC)
+	acme := FieldSource{Supplier: "acme", FetchedAt: acmeFetchedAt}
+	paperflies := FieldSource{Supplier: "paperflies", FetchedAt: paperfliesFetchedAt}
+	previous := FieldSource{Supplier: "patagonia", FetchedAt: previousFetchedAt}
+
+	updatedData := []supplierHotelsData{
+		{
+			supplier:  Supplier{Name: "acme", Priority: 1},
+			fetchedAt: acmeFetchedAt,
+			hotels: []Hotel{{
+				ID: "SjyX", DestinationID: 5432, HotelName: "InterContinental Singapore Robertson Quay",
+				Location:  Location{City: "Singapore", Country: "SG"},
+				Amenities: Amenities{General: []string{"Pool", "WiFi"}},
+			}},
+		},
+		{
+			supplier:  Supplier{Name: "paperflies", Priority: 2},
+			fetchedAt: paperfliesFetchedAt,
+			hotels: []Hotel{{
+				ID: "SjyX", DestinationID: 5432, HotelName: "InterContinental",
+				Location:  Location{Address: "1 Nanson Rd, Singapore 238909", Country: "Singapore"},
+				Amenities: Amenities{General: []string{"WiFi"}},
+				Images: map[string][]Image{
+					"site": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i1_m.jpg", Description: "Restaurant"}},
+				},
+			}},
+		},
+	}
+	currentHotelData := map[string]Hotel{
+		"SjyX": {
+			ID:        "SjyX",
+			Location:  Location{Lat: 1.28967},
+			Amenities: Amenities{General: []string{"Bar"}},
+			Provenance: &HotelProvenance{
+				Fields:   map[string]FieldSource{MergeFieldLat: previous},
+				Elements: map[string]map[string]FieldSource{ProvenanceFieldGeneralAmenities: {"Bar": previous}},
+			},
+		},
+	}
+
+	logger := logging.LogrusLogger()
+	hotelService := &hotelServiceImpl{logger: logger, ctx: context.Background(), mergePolicy: DefaultMergePolicy()}
+	hotelService.sanitizeHotelData(updatedData, currentHotelData)
+
+	assert.Equal(t, &HotelProvenance{
+		Fields: map[string]FieldSource{
+			MergeFieldDestinationID: paperflies,
+			MergeFieldHotelName:     paperflies,
+			MergeFieldLat:           previous,
+			MergeFieldAddress:       paperflies,
+			MergeFieldCity:          acme,
+			MergeFieldCountry:       paperflies,
+		},
+		Elements: map[string]map[string]FieldSource{
+			ProvenanceFieldGeneralAmenities: {"bar": previous, "wifi": paperflies, "pool": acme},
+			MergeFieldImages:                {"https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i1_m.jpg": paperflies},
+		},
+		LastSeen: map[string]time.Time{"acme": acmeFetchedAt, "paperflies": paperfliesFetchedAt},
+	}, currentHotelData["SjyX"].Provenance)
+}
@@ -0,0 +1,88 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// QuarantinedRecord is a supplier record that failed normalization or validation, kept for review.
+type QuarantinedRecord struct {
+	Supplier      string            `json:"supplier"`
+	HotelID       string            `json:"hotel_id,omitempty"`
+	QuarantinedAt time.Time         `json:"quarantined_at"`
+	Violations    []RecordViolation `json:"violations"`
+	Record        json.RawMessage   `json:"record"`
+}
+
+// QuarantineStore keeps the records quarantined by the last update of each supplier in a JSON file.
+// A record fixed by its supplier leaves the quarantine with the next update of that supplier.
+type QuarantineStore struct {
+	mu       sync.Mutex
+	filePath string
+}
+
+func NewQuarantineStore(filePath string) *QuarantineStore {
+	return &QuarantineStore{filePath: filePath}
+}
+
+// List returns the quarantined records of a supplier, or of every supplier when supplier is empty.
+func (s *QuarantineStore) List(supplier string) ([]QuarantinedRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	records, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	filtered := make([]QuarantinedRecord, 0, len(records))
+	for _, record := range records {
+		if supplier == "" || record.Supplier == supplier {
+			filtered = append(filtered, record)
+		}
+	}
+	return filtered, nil
+}
+
+// replace swaps the quarantined records of the given suppliers with the records of their last update.
+func (s *QuarantineStore) replace(suppliers []string, records []QuarantinedRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stored, err := s.read()
+	if err != nil {
+		return err
+	}
+	kept := make([]QuarantinedRecord, 0, len(stored)+len(records))
+	for _, record := range stored {
+		if !utils.SliceContains(suppliers, record.Supplier) {
+			kept = append(kept, record)
+		}
+	}
+	kept = append(kept, records...)
+	sort.SliceStable(kept, func(i, j int) bool {
+		if kept[i].Supplier != kept[j].Supplier {
+			return kept[i].Supplier < kept[j].Supplier
+		}
+		return kept[i].HotelID < kept[j].HotelID
+	})
+	return utils.WriteJSONFile(s.filePath, kept)
+}
+
+func (s *QuarantineStore) read() ([]QuarantinedRecord, error) {
+	data, err := os.ReadFile(s.filePath)
+	if errors.Is(err, os.ErrNotExist) || (err == nil && len(data) == 0) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var records []QuarantinedRecord
+	if err := json.Unmarshal(data, &records); err != nil {
+		return nil, fmt.Errorf("invalid quarantine file %s: %w", s.filePath, err)
+	}
+	return records, nil
+}
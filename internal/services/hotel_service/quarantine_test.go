@@ -0,0 +1,93 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestQuarantineStore(t *testing.T) {
+	store := NewQuarantineStore(filepath.Join(t.TempDir(), "quarantine.json"))
+	records, err := store.List("")
+	assert.Nil(t, err)
+	assert.Empty(t, records)
+
+	violations := []RecordViolation{{Field: ValidationFieldID, Rule: ValidationRuleRequired, Message: "id is required"}}
+	assert.Nil(t, store.replace([]string{"acme", "patagonia"}, []QuarantinedRecord{
+		{Supplier: "patagonia", HotelID: "f8c9", Violations: violations, Record: json.RawMessage(`{"id": "f8c9"}`)},
+		{Supplier: "acme", HotelID: "SjyX", Violations: violations, Record: json.RawMessage(`{"Id": "SjyX"}`)},
+	}))
+	records, err = store.List("")
+	assert.Nil(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "acme", records[0].Supplier)
+
+	// the next update of acme has no invalid record, the patagonia records are kept
+	assert.Nil(t, store.replace([]string{"acme"}, nil))
+	records, err = store.List("")
+	assert.Nil(t, err)
+	assert.Len(t, records, 1)
+	assert.Equal(t, "patagonia", records[0].Supplier)
+	assert.JSONEq(t, `{"id": "f8c9"}`, string(records[0].Record))
+
+	records, err = store.List("acme")
+	assert.Nil(t, err)
+	assert.Empty(t, records)
+}
+
+func TestUpdateHotelsFromSuppliersQuarantine(t *testing.T) {
+	mockClient := &MockHTTPClient{
+		Responses: map[string]*http.Response{
+			"https://example.com/hotels": {
+				StatusCode: http.StatusOK,
+				Body: io.NopCloser(strings.NewReader(`[
+					{"Id": "n3w1", "DestinationId": 5432, "Name": "New Hotel"},
+					{"Id": "n3w2", "DestinationId": 5432, "Name": "Far Hotel", "Latitude": 123.4},
+					{"DestinationId": 5432, "Name": "No Id"}
+				]`)),
+			},
+		},
+	}
+	logger := logging.LogrusLogger()
+	wd, _ := os.Getwd()
+	repository := NewJSONHotelRepository(logger, copyTestDataFile(t, "test_sanitize_data.json"))
+	previousHotels, err := repository.Snapshot()
+	assert.Nil(t, err)
+	quarantine := NewQuarantineStore(filepath.Join(t.TempDir(), "quarantine.json"))
+	config := Config{
+		Repository:        repository,
+		SuppliersFilePath: filepath.Join(wd, "test_data", "test_suppliers.json"),
+		Adapters:          map[string]SupplierAdapter{"": &acmeAdapter{}},
+		Quarantine:        quarantine,
+	}
+	hotelService := NewHotelService(logger, mockClient, context.Background(), config)
+
+	result, err := hotelService.UpdateHotelsFromSuppliers(UpdateOptions{})
+	assert.Nil(t, err)
+	assert.Equal(t, 1, result.Diff.HotelsAdded)
+	assert.Equal(t, 2, result.Suppliers[0].RecordsRejected)
+	assert.Len(t, result.Warnings, 2)
+	hotels, err := repository.Snapshot()
+	assert.Nil(t, err)
+	assert.Len(t, hotels, len(previousHotels)+1)
+
+	records, err := hotelService.GetQuarantine("example")
+	assert.Nil(t, err)
+	assert.Len(t, records, 2)
+	assert.Equal(t, "", records[0].HotelID)
+	assert.Equal(t, ValidationRuleInvalidRecord, records[0].Violations[0].Rule)
+	assert.Equal(t, "n3w2", records[1].HotelID)
+	assert.Equal(t, []RecordViolation{{
+		Field:   ValidationFieldLat,
+		Rule:    ValidationRuleRange,
+		Message: "latitude 123.4 is out of range [-90, 90]",
+	}}, records[1].Violations)
+	assert.JSONEq(t, `{"Id": "n3w2", "DestinationId": 5432, "Name": "Far Hotel", "Latitude": 123.4}`, string(records[1].Record))
+}
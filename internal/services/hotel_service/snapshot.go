@@ -0,0 +1,222 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	snapshotFilePrefix     = "snapshot-"
+	snapshotInfoFileSuffix = ".info.json"
+)
+
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// SnapshotInfo describes a catalog version. RestoredFrom is set when the version was produced by a rollback,
+// UpdateID when the change that produced it was recorded (see UpdateDiffStore). Baseline is set on the version
+// holding the catalog stored before the first update.
+type SnapshotInfo struct {
+	ID           int       `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	Sources      []string  `json:"sources,omitempty"`
+	HotelCount   int       `json:"hotel_count"`
+	RestoredFrom int       `json:"restored_from,omitempty"`
+	UpdateID     int       `json:"update_id,omitempty"`
+	Baseline     bool      `json:"baseline,omitempty"`
+}
+
+type catalogSnapshot struct {
+	SnapshotInfo
+	Hotels map[string]Hotel `json:"hotels"`
+}
+
+// SnapshotStore keeps every catalog version as a numbered JSON file of a directory, along with a small info
+// file holding its SnapshotInfo so listing the versions never decodes the catalogs.
+// When maxSnapshots is positive only the most recent versions are kept.
+type SnapshotStore struct {
+	mu           sync.Mutex
+	dirPath      string
+	maxSnapshots int
+}
+
+func NewSnapshotStore(dirPath string, maxSnapshots int) (*SnapshotStore, error) {
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return nil, fmt.Errorf("error creating snapshot directory %s: %w", dirPath, err)
+	}
+	return &SnapshotStore{dirPath: dirPath, maxSnapshots: maxSnapshots}, nil
+}
+
+// Save stores the hotels as the next version, the id, creation time and hotel count of info are filled in.
+func (s *SnapshotStore) Save(hotels map[string]Hotel, info SnapshotInfo) (SnapshotInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.ids()
+	if err != nil {
+		return SnapshotInfo{}, err
+	}
+	return s.save(ids, hotels, info)
+}
+
+// SaveBaseline stores the hotels as the first version when there is no version yet, so the first update
+// can be rolled back. It returns false when versions already exist.
+func (s *SnapshotStore) SaveBaseline(hotels map[string]Hotel) (SnapshotInfo, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.ids()
+	if err != nil || len(ids) > 0 {
+		return SnapshotInfo{}, false, err
+	}
+	info, err := s.save(ids, hotels, SnapshotInfo{Baseline: true})
+	if err != nil {
+		return SnapshotInfo{}, false, err
+	}
+	return info, true, nil
+}
+
+// save stores the next version after the given ones and rotates the oldest.
+func (s *SnapshotStore) save(ids []int, hotels map[string]Hotel, info SnapshotInfo) (SnapshotInfo, error) {
+	nextID := 1
+	if len(ids) > 0 {
+		nextID = ids[len(ids)-1] + 1
+	}
+	info.ID = nextID
+	info.CreatedAt = time.Now().UTC()
+	info.HotelCount = len(hotels)
+	snapshot := catalogSnapshot{SnapshotInfo: info, Hotels: hotels}
+	// the info file is written first, a version is only listed once its catalog file exists
+	if err := utils.WriteJSONFile(s.infoFilePath(nextID), info); err != nil {
+		return SnapshotInfo{}, fmt.Errorf("error writing snapshot info %d: %w", nextID, err)
+	}
+	if err := utils.WriteJSONFile(s.filePath(nextID), snapshot); err != nil {
+		return SnapshotInfo{}, fmt.Errorf("error writing snapshot %d: %w", nextID, err)
+	}
+
+	ids = append(ids, nextID)
+	if s.maxSnapshots > 0 && len(ids) > s.maxSnapshots {
+		for _, id := range ids[:len(ids)-s.maxSnapshots] {
+			if err := os.Remove(s.filePath(id)); err != nil {
+				return snapshot.SnapshotInfo, fmt.Errorf("error removing snapshot %d: %w", id, err)
+			}
+			if err := os.Remove(s.infoFilePath(id)); err != nil && !os.IsNotExist(err) {
+				return snapshot.SnapshotInfo, fmt.Errorf("error removing snapshot info %d: %w", id, err)
+			}
+		}
+	}
+	return snapshot.SnapshotInfo, nil
+}
+
+// List returns the stored versions, the most recent first.
+func (s *SnapshotStore) List() ([]SnapshotInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := s.ids()
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]SnapshotInfo, 0, len(ids))
+	for i := len(ids) - 1; i >= 0; i-- {
+		info, err := s.readInfo(ids[i])
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
+
+// readInfo reads the info file of a version, versions saved without one are read from their catalog file.
+func (s *SnapshotStore) readInfo(id int) (SnapshotInfo, error) {
+	var info SnapshotInfo
+	data, err := utils.ReadJSONFile(s.infoFilePath(id))
+	if os.IsNotExist(err) {
+		err = s.read(id, &info)
+		return info, err
+	}
+	if err != nil {
+		return SnapshotInfo{}, fmt.Errorf("error reading snapshot info %d: %w", id, err)
+	}
+	if err := json.Unmarshal(data, &info); err != nil {
+		return SnapshotInfo{}, fmt.Errorf("error decoding snapshot info %d: %w", id, err)
+	}
+	return info, nil
+}
+
+// Load returns the hotels of a version, or ErrSnapshotNotFound.
+func (s *SnapshotStore) Load(id int) (SnapshotInfo, map[string]Hotel, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var snapshot catalogSnapshot
+	if err := s.read(id, &snapshot); err != nil {
+		return SnapshotInfo{}, nil, err
+	}
+	if snapshot.Hotels == nil {
+		snapshot.Hotels = map[string]Hotel{}
+	}
+	return snapshot.SnapshotInfo, snapshot.Hotels, nil
+}
+
+func (s *SnapshotStore) read(id int, snapshot interface{}) error {
+	data, err := utils.ReadJSONFile(s.filePath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return fmt.Errorf("%w: %d", ErrSnapshotNotFound, id)
+		}
+		return fmt.Errorf("error reading snapshot %d: %w", id, err)
+	}
+	if err := json.Unmarshal(data, snapshot); err != nil {
+		return fmt.Errorf("error decoding snapshot %d: %w", id, err)
+	}
+	return nil
+}
+
+// ids returns the stored version numbers in ascending order.
+func (s *SnapshotStore) ids() ([]int, error) {
+	ids, err := numberedFileIds(s.dirPath, snapshotFilePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("error listing snapshots in %s: %w", s.dirPath, err)
+	}
+	return ids, nil
+}
+
+// numberedFileIds returns in ascending order the numbers of the <prefix><number>.json files of a directory.
+func numberedFileIds(dirPath string, prefix string) ([]int, error) {
+	entries, err := os.ReadDir(dirPath)
+	if err != nil {
+		return nil, err
+	}
+	var ids []int
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || filepath.Ext(name) != ".json" {
+			continue
+		}
+		id, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".json"))
+		if err != nil {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+func (s *SnapshotStore) filePath(id int) string {
+	return filepath.Join(s.dirPath, fmt.Sprintf("%s%d.json", snapshotFilePrefix, id))
+}
+
+func (s *SnapshotStore) infoFilePath(id int) string {
+	return filepath.Join(s.dirPath, fmt.Sprintf("%s%d%s", snapshotFilePrefix, id, snapshotInfoFileSuffix))
+}
@@ -0,0 +1,187 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSnapshotStore(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "snapshots")
+	store, err := NewSnapshotStore(dir, 2)
+	assert.Nil(t, err)
+
+	versions := []map[string]Hotel{
+		{"iJhz": {ID: "iJhz", HotelName: "Beach Villas Singapore"}},
+		{"iJhz": {ID: "iJhz", HotelName: "Beach Villas"}, "f8c9": {ID: "f8c9"}},
+		{"f8c9": {ID: "f8c9", HotelName: "Hilton Tokyo Shinjuku"}},
+	}
+	for i, hotels := range versions {
+		info, err := store.Save(hotels, SnapshotInfo{Sources: []string{"acme"}})
+		assert.Nil(t, err)
+		assert.Equal(t, i+1, info.ID)
+		assert.Equal(t, len(hotels), info.HotelCount)
+	}
+
+	snapshots, err := store.List()
+	assert.Nil(t, err)
+	ids := make([]int, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		ids = append(ids, snapshot.ID)
+	}
+	assert.Equal(t, []int{3, 2}, ids)
+
+	info, hotels, err := store.Load(2)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"acme"}, info.Sources)
+	assert.Equal(t, versions[1], hotels)
+
+	_, _, err = store.Load(1)
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+
+	// the rotated versions leave no file behind
+	entries, err := os.ReadDir(dir)
+	assert.Nil(t, err)
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"snapshot-2.json", "snapshot-2.info.json", "snapshot-3.json", "snapshot-3.info.json"}, names)
+
+	// listing only reads the info files, a version saved without one is read from its catalog file
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "snapshot-3.json"), []byte(`{"hotels": `), 0644))
+	assert.Nil(t, os.Remove(filepath.Join(dir, "snapshot-2.info.json")))
+	snapshots, err = store.List()
+	assert.Nil(t, err)
+	if assert.Len(t, snapshots, 2) {
+		assert.Equal(t, 3, snapshots[0].ID)
+		assert.Equal(t, 2, snapshots[1].ID)
+		assert.Equal(t, 2, snapshots[1].HotelCount)
+	}
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	dir := t.TempDir()
+	logger := logging.LogrusLogger()
+	repository := NewJSONHotelRepository(logger, filepath.Join(dir, "hotels.json"))
+	snapshots, err := NewSnapshotStore(filepath.Join(dir, "snapshots"), 0)
+	assert.Nil(t, err)
+	updateDiffs, err := NewUpdateDiffStore(filepath.Join(dir, "update_diffs"))
+	assert.Nil(t, err)
+
+	good := map[string]Hotel{"iJhz": {ID: "iJhz", DestinationID: 5432, HotelName: "Beach Villas Singapore"}}
+	bad := map[string]Hotel{
+		"iJhz": {ID: "iJhz", DestinationID: 5432, HotelName: "???"},
+		"f8c9": {ID: "f8c9", DestinationID: 1122},
+	}
+	_, err = snapshots.Save(good, SnapshotInfo{Sources: []string{"acme"}})
+	assert.Nil(t, err)
+	_, err = snapshots.Save(bad, SnapshotInfo{Sources: []string{"acme"}})
+	assert.Nil(t, err)
+	assert.Nil(t, repository.Upsert(bad["iJhz"], bad["f8c9"]))
+	catalog, err := NewCatalog(repository)
+	assert.Nil(t, err)
+
+	hotelService := NewHotelService(logger, nil, context.Background(), Config{
+		Repository:  repository,
+		Catalog:     catalog,
+		Snapshots:   snapshots,
+		UpdateDiffs: updateDiffs,
+	})
+
+	testCases := []struct {
+		description      string
+		id               int
+		expectedNotFound bool
+		expectedInfo     SnapshotInfo
+	}{
+		{
+			description:      "unknown snapshot",
+			id:               7,
+			expectedNotFound: true,
+		},
+		{
+			description:  "restore a previous snapshot as a new version",
+			id:           1,
+			expectedInfo: SnapshotInfo{ID: 3, Sources: []string{"acme"}, HotelCount: 1, RestoredFrom: 1, UpdateID: 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			info, err := hotelService.RestoreSnapshot(tc.id)
+			if tc.expectedNotFound {
+				assert.ErrorIs(t, err, ErrSnapshotNotFound)
+				return
+			}
+			assert.Nil(t, err)
+			info.CreatedAt = tc.expectedInfo.CreatedAt
+			assert.Equal(t, tc.expectedInfo, info)
+
+			stored, err := repository.Snapshot()
+			assert.Nil(t, err)
+			assert.Equal(t, good, stored)
+			assert.Equal(t, []Hotel{}, catalog.FindByDestination(1122))
+
+			diff, err := hotelService.GetUpdateDiff(info.UpdateID)
+			assert.Nil(t, err)
+			assert.Equal(t, CatalogDiff{
+				Added:   []string{},
+				Removed: []string{"f8c9"},
+				Changed: []HotelChange{{
+					HotelID: "iJhz",
+					Fields:  []FieldChange{{Field: MergeFieldHotelName, Before: "???", After: "Beach Villas Singapore"}},
+				}},
+			}, diff)
+		})
+	}
+}
+
+func TestUpdateHotelsFromSuppliersBaselineSnapshot(t *testing.T) {
+	mockClient := &MockHTTPClient{Responses: map[string]*http.Response{}}
+	logger := logging.LogrusLogger()
+	wd, _ := os.Getwd()
+	repository := NewJSONHotelRepository(logger, copyTestDataFile(t, "test_sanitize_data.json"))
+	original, err := repository.Snapshot()
+	assert.Nil(t, err)
+	snapshots, err := NewSnapshotStore(filepath.Join(t.TempDir(), "snapshots"), 0)
+	assert.Nil(t, err)
+	hotelService := NewHotelService(logger, mockClient, context.Background(), Config{
+		Repository:        repository,
+		SuppliersFilePath: filepath.Join(wd, "test_data", "test_suppliers.json"),
+		Adapters:          map[string]SupplierAdapter{"": &acmeAdapter{}},
+		Snapshots:         snapshots,
+	})
+	update := func() UpdateResult {
+		mockClient.Responses["https://example.com/hotels"] = &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`[{"Id": "n3w1", "DestinationId": 5432, "Name": "New Hotel"}]`)),
+		}
+		result, err := hotelService.UpdateHotelsFromSuppliers(UpdateOptions{})
+		assert.Nil(t, err)
+		return result
+	}
+
+	// the catalog stored before the first update is saved as a baseline version
+	assert.Equal(t, 2, update().SnapshotID)
+	assert.Equal(t, 3, update().SnapshotID)
+	infos, err := snapshots.List()
+	assert.Nil(t, err)
+	assert.Len(t, infos, 3)
+	baseline := infos[len(infos)-1]
+	assert.Equal(t, 1, baseline.ID)
+	assert.True(t, baseline.Baseline)
+
+	// the first update can be rolled back
+	_, err = hotelService.RestoreSnapshot(baseline.ID)
+	assert.Nil(t, err)
+	hotels, err := repository.Snapshot()
+	assert.Nil(t, err)
+	assert.Equal(t, original, hotels)
+}
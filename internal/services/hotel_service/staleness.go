@@ -0,0 +1,186 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/utils"
+	"encoding/json"
+	"fmt"
+)
+
+type StalenessAction string
+
+const (
+	// StalenessActionRemove deletes hotels that were missed too many times.
+	StalenessActionRemove StalenessAction = "remove"
+	// StalenessActionInactive keeps hotels that were missed too many times but marks them inactive.
+	StalenessActionInactive StalenessAction = "inactive"
+)
+
+// StalenessPolicy decides what happens to hotels and values the suppliers stop reporting.
+// A hotel is missed by an update when at least one of the suppliers that reported it before was fetched
+// and none of them reported it again. MaxMissedUpdates zero never acts on missed hotels.
+// With DropWithdrawnValues, a value is dropped when the supplier it came from reports the hotel without it.
+type StalenessPolicy struct {
+	MaxMissedUpdates    int             `json:"max_missed_updates"`
+	Action              StalenessAction `json:"action"`
+	DropWithdrawnValues bool            `json:"drop_withdrawn_values"`
+}
+
+func LoadStalenessPolicy(filePath string) (StalenessPolicy, error) {
+	data, err := utils.ReadJSONFile(filePath)
+	if err != nil {
+		return StalenessPolicy{}, err
+	}
+	var policy StalenessPolicy
+	err = json.Unmarshal(data, &policy)
+	if err != nil {
+		return StalenessPolicy{}, fmt.Errorf("invalid staleness policy file %s: %w", filePath, err)
+	}
+	if policy.MaxMissedUpdates < 0 {
+		return StalenessPolicy{}, fmt.Errorf("max_missed_updates must not be negative")
+	}
+	if policy.MaxMissedUpdates > 0 && policy.Action != StalenessActionRemove && policy.Action != StalenessActionInactive {
+		return StalenessPolicy{}, fmt.Errorf("unknown staleness action %q", policy.Action)
+	}
+	return policy, nil
+}
+
+// applyStaleness counts the updates that missed each hotel not reported this time and applies the policy.
+// fetchedSuppliers holds the suppliers whose data was fetched by the update.
+func (h *hotelServiceImpl) applyStaleness(
+	currentHotelData map[string]Hotel,
+	reportedHotelIds map[string]bool,
+	fetchedSuppliers map[string]bool,
+) {
+	if len(fetchedSuppliers) == 0 {
+		return
+	}
+	policy := h.stalenessPolicy
+	for _, id := range sortedHotelIds(currentHotelData) {
+		if reportedHotelIds[id] {
+			continue
+		}
+		hotel := currentHotelData[id]
+		if !isMissedByUpdate(hotel, fetchedSuppliers) {
+			continue
+		}
+
+		if hotel.Provenance == nil {
+			hotel.Provenance = newHotelProvenance()
+		}
+		hotel.Provenance.MissedUpdates++
+		if policy.MaxMissedUpdates > 0 && hotel.Provenance.MissedUpdates >= policy.MaxMissedUpdates {
+			switch policy.Action {
+			case StalenessActionRemove:
+				h.logger.Info(fmt.Sprintf("Removing hotel %s, missed by %d updates", id, hotel.Provenance.MissedUpdates))
+				delete(currentHotelData, id)
+				continue
+			case StalenessActionInactive:
+				hotel.Inactive = true
+			}
+		}
+		currentHotelData[id] = hotel
+	}
+}
+
+// isMissedByUpdate tells whether a supplier that reported the hotel before was fetched,
+// hotels without last-seen records are missed by any update.
+func isMissedByUpdate(hotel Hotel, fetchedSuppliers map[string]bool) bool {
+	if hotel.Provenance == nil || len(hotel.Provenance.LastSeen) == 0 {
+		return true
+	}
+	for supplier := range hotel.Provenance.LastSeen {
+		if fetchedSuppliers[supplier] {
+			return true
+		}
+	}
+	return false
+}
+
+// dropWithdrawnValues removes from a stored hotel the values whose supplier reported the hotel again,
+// in the freshly merged hotel, without them. Values without provenance are kept.
+func dropWithdrawnValues(stored *Hotel, fresh Hotel) {
+	if stored.Provenance == nil || fresh.Provenance == nil {
+		return
+	}
+	reportedBy := make(map[string]bool, len(fresh.Provenance.LastSeen))
+	for supplier := range fresh.Provenance.LastSeen {
+		reportedBy[supplier] = true
+	}
+	withdrawn := func(source FieldSource, exists bool) bool {
+		return exists && reportedBy[source.Supplier]
+	}
+
+	scalars := []struct {
+		field   string
+		missing bool
+		clear   func()
+	}{
+		{MergeFieldDestinationID, fresh.DestinationID == 0, func() { stored.DestinationID = 0 }},
+		{MergeFieldHotelName, fresh.HotelName == "", func() { stored.HotelName = "" }},
+		{MergeFieldLat, fresh.Location.Lat == 0, func() { stored.Location.Lat = 0 }},
+		{MergeFieldLng, fresh.Location.Long == 0, func() { stored.Location.Long = 0 }},
+		{MergeFieldAddress, fresh.Location.Address == "", func() { stored.Location.Address = "" }},
+		{MergeFieldCity, fresh.Location.City == "", func() { stored.Location.City = "" }},
+		{MergeFieldCountry, fresh.Location.Country == "", func() {
+			stored.Location.Country = ""
+			stored.Location.CountryName = ""
+		}},
+	}
+	for _, scalar := range scalars {
+		source, exists := stored.Provenance.Fields[scalar.field]
+		if scalar.missing && withdrawn(source, exists) {
+			scalar.clear()
+			delete(stored.Provenance.Fields, scalar.field)
+		}
+	}
+
+	keepReported := func(field string, values []string, freshValues []string) []string {
+		freshSet := make(map[string]bool, len(freshValues))
+		for _, value := range freshValues {
+			freshSet[value] = true
+		}
+		var kept []string
+		for _, value := range values {
+			source, exists := stored.Provenance.Elements[field][value]
+			if !freshSet[value] && withdrawn(source, exists) {
+				delete(stored.Provenance.Elements[field], value)
+				continue
+			}
+			kept = append(kept, value)
+		}
+		return kept
+	}
+	freshDescriptions := append(append([]string{}, fresh.Description...), fresh.DescriptionAlternates...)
+	stored.Description = keepReported(MergeFieldDescription, stored.Description, freshDescriptions)
+	stored.DescriptionAlternates = keepReported(MergeFieldDescription, stored.DescriptionAlternates, freshDescriptions)
+	stored.Amenities.General = keepReported(ProvenanceFieldGeneralAmenities, stored.Amenities.General, fresh.Amenities.General)
+	stored.Amenities.Room = keepReported(ProvenanceFieldRoomAmenities, stored.Amenities.Room, fresh.Amenities.Room)
+	stored.BookingCondition = keepReported(MergeFieldBookingConditions, stored.BookingCondition, fresh.BookingCondition)
+
+	// images are compared, and their provenance keyed, by canonical link
+	var freshLinks []string
+	for _, link := range imageLinks(fresh.Images) {
+		freshLinks = append(freshLinks, canonicalImageURL(link))
+	}
+	for category, images := range stored.Images {
+		links := make([]string, 0, len(images))
+		for _, image := range images {
+			links = append(links, canonicalImageURL(image.Link))
+		}
+		keptLinks := make(map[string]bool)
+		for _, link := range keepReported(MergeFieldImages, links, freshLinks) {
+			keptLinks[link] = true
+		}
+		var keptImages []Image
+		for _, image := range images {
+			if keptLinks[canonicalImageURL(image.Link)] {
+				keptImages = append(keptImages, image)
+			}
+		}
+		if len(keptImages) == 0 {
+			delete(stored.Images, category)
+			continue
+		}
+		stored.Images[category] = keptImages
+	}
+}
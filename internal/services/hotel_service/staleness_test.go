@@ -0,0 +1,249 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestApplyStaleness(t *testing.T) {
+	seenAt := time.Date(2024, 11, 1, 8, 0, 0, 0, time.UTC)
+	staleHotel := func(missedUpdates int) Hotel {
+		return Hotel{
+			ID:        "SjyX",
+			HotelName: "InterContinental",
+			Provenance: &HotelProvenance{
+				LastSeen:      map[string]time.Time{"acme": seenAt},
+				MissedUpdates: missedUpdates,
+			},
+		}
+	}
+
+	testCases := []struct {
+		description      string
+		policy           StalenessPolicy
+		hotel            Hotel
+		reported         bool
+		fetchedSuppliers map[string]bool
+		expectedHotel    *Hotel
+	}{
+		{
+			description:      "count a missed update",
+			policy:           StalenessPolicy{MaxMissedUpdates: 3, Action: StalenessActionRemove},
+			hotel:            staleHotel(0),
+			fetchedSuppliers: map[string]bool{"acme": true},
+			expectedHotel: &Hotel{
+				ID:        "SjyX",
+				HotelName: "InterContinental",
+				Provenance: &HotelProvenance{
+					LastSeen:      map[string]time.Time{"acme": seenAt},
+					MissedUpdates: 1,
+				},
+			},
+		},
+		{
+			description:      "remove a hotel missed too many times",
+			policy:           StalenessPolicy{MaxMissedUpdates: 3, Action: StalenessActionRemove},
+			hotel:            staleHotel(2),
+			fetchedSuppliers: map[string]bool{"acme": true},
+			expectedHotel:    nil,
+		},
+		{
+			description:      "mark a hotel missed too many times inactive",
+			policy:           StalenessPolicy{MaxMissedUpdates: 3, Action: StalenessActionInactive},
+			hotel:            staleHotel(2),
+			fetchedSuppliers: map[string]bool{"acme": true},
+			expectedHotel: &Hotel{
+				ID:        "SjyX",
+				HotelName: "InterContinental",
+				Inactive:  true,
+				Provenance: &HotelProvenance{
+					LastSeen:      map[string]time.Time{"acme": seenAt},
+					MissedUpdates: 3,
+				},
+			},
+		},
+		{
+			description:      "ignore updates that did not fetch the suppliers of the hotel",
+			policy:           StalenessPolicy{MaxMissedUpdates: 1, Action: StalenessActionRemove},
+			hotel:            staleHotel(0),
+			fetchedSuppliers: map[string]bool{"paperflies": true},
+			expectedHotel: &Hotel{
+				ID:        "SjyX",
+				HotelName: "InterContinental",
+				Provenance: &HotelProvenance{
+					LastSeen: map[string]time.Time{"acme": seenAt},
+				},
+			},
+		},
+		{
+			description:      "keep reported hotels",
+			policy:           StalenessPolicy{MaxMissedUpdates: 1, Action: StalenessActionRemove},
+			hotel:            staleHotel(0),
+			reported:         true,
+			fetchedSuppliers: map[string]bool{"acme": true},
+			expectedHotel: &Hotel{
+				ID:        "SjyX",
+				HotelName: "InterContinental",
+				Provenance: &HotelProvenance{
+					LastSeen: map[string]time.Time{"acme": seenAt},
+				},
+			},
+		},
+		{
+			description:      "never act without a max missed updates",
+			policy:           StalenessPolicy{Action: StalenessActionRemove},
+			hotel:            staleHotel(5),
+			fetchedSuppliers: map[string]bool{"acme": true},
+			expectedHotel: &Hotel{
+				ID:        "SjyX",
+				HotelName: "InterContinental",
+				Provenance: &HotelProvenance{
+					LastSeen:      map[string]time.Time{"acme": seenAt},
+					MissedUpdates: 6,
+				},
+			},
+		},
+	}
+
+	logger := logging.LogrusLogger()
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			hotelService := &hotelServiceImpl{logger: logger, ctx: context.Background(), stalenessPolicy: tc.policy}
+			currentHotelData := map[string]Hotel{tc.hotel.ID: tc.hotel}
+			hotelService.applyStaleness(currentHotelData, map[string]bool{tc.hotel.ID: tc.reported}, tc.fetchedSuppliers)
+
+			hotel, exists := currentHotelData[tc.hotel.ID]
+			if tc.expectedHotel == nil {
+				assert.False(t, exists)
+				return
+			}
+			assert.Equal(t, *tc.expectedHotel, hotel)
+		})
+	}
+}
+
+func TestSanitizeHotelDataStaleness(t *testing.T) {
+	previousFetchedAt := time.Date(2024, 10, 1, 8, 0, 0, 0, time.UTC)
+	fetchedAt := time.Date(2024, 11, 1, 8, 0, 0, 0, time.UTC)
+	previous := FieldSource{Supplier: "acme", FetchedAt: previousFetchedAt}
+
+	currentHotelData := map[string]Hotel{
+		"SjyX": {
+			ID:        "SjyX",
+			HotelName: "InterContinental",
+			Amenities: Amenities{General: []string{"pool", "wifi"}},
+			Inactive:  true,
+			Provenance: &HotelProvenance{
+				Fields:        map[string]FieldSource{MergeFieldHotelName: previous},
+				Elements:      map[string]map[string]FieldSource{ProvenanceFieldGeneralAmenities: {"pool": previous, "wifi": previous}},
+				LastSeen:      map[string]time.Time{"acme": previousFetchedAt},
+				MissedUpdates: 2,
+			},
+		},
+	}
+	updatedData := []supplierHotelsData{
+		{
+			supplier:  Supplier{Name: "acme", Priority: 1},
+			fetchedAt: fetchedAt,
+			hotels: []Hotel{{
+				ID:        "SjyX",
+				HotelName: "InterContinental Singapore",
+				Amenities: Amenities{General: []string{"WiFi"}},
+			}},
+		},
+	}
+
+	logger := logging.LogrusLogger()
+	hotelService := &hotelServiceImpl{
+		logger:          logger,
+		ctx:             context.Background(),
+		mergePolicy:     DefaultMergePolicy(),
+		stalenessPolicy: StalenessPolicy{MaxMissedUpdates: 3, Action: StalenessActionInactive, DropWithdrawnValues: true},
+	}
+	hotelService.sanitizeHotelData(updatedData, currentHotelData)
+
+	current := FieldSource{Supplier: "acme", FetchedAt: fetchedAt}
+	assert.Equal(t, Hotel{
+		ID:        "SjyX",
+		HotelName: "InterContinental Singapore",
+		Amenities: Amenities{General: []string{"wifi"}},
+		Provenance: &HotelProvenance{
+			Fields:   map[string]FieldSource{MergeFieldHotelName: current},
+			Elements: map[string]map[string]FieldSource{ProvenanceFieldGeneralAmenities: {"wifi": current}},
+			LastSeen: map[string]time.Time{"acme": fetchedAt},
+		},
+	}, currentHotelData["SjyX"])
+}
+
+func TestDropWithdrawnValues(t *testing.T) {
+	acme := FieldSource{Supplier: "acme", FetchedAt: time.Date(2024, 10, 1, 8, 0, 0, 0, time.UTC)}
+	paperflies := FieldSource{Supplier: "paperflies", FetchedAt: time.Date(2024, 10, 1, 8, 0, 0, 0, time.UTC)}
+	stored := Hotel{
+		ID:          "SjyX",
+		HotelName:   "InterContinental",
+		Location:    Location{Address: "1 Nanson Rd", City: "Singapore"},
+		Description: []string{"Close to the river."},
+		Amenities:   Amenities{General: []string{"pool", "bar"}},
+		Images: map[string][]Image{
+			"site": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i1_m.jpg"}},
+			"rooms": {
+				{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i2_m.jpg"},
+				{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i3_m.jpg"},
+			},
+		},
+		Provenance: &HotelProvenance{
+			Fields: map[string]FieldSource{
+				MergeFieldHotelName: acme,
+				MergeFieldAddress:   acme,
+				MergeFieldCity:      paperflies,
+			},
+			Elements: map[string]map[string]FieldSource{
+				MergeFieldDescription:           {"Close to the river.": acme},
+				ProvenanceFieldGeneralAmenities: {"pool": acme, "bar": paperflies},
+				MergeFieldImages: {
+					"https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i1_m.jpg": acme,
+					"https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i2_m.jpg": acme,
+					"https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i3_m.jpg": paperflies,
+				},
+			},
+		},
+	}
+	fresh := Hotel{
+		ID:        "SjyX",
+		HotelName: "InterContinental Singapore",
+		Images: map[string][]Image{
+			"site": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i1_m.jpg"}},
+		},
+		Provenance: &HotelProvenance{LastSeen: map[string]time.Time{"acme": acme.FetchedAt}},
+	}
+
+	dropWithdrawnValues(&stored, fresh)
+
+	assert.Equal(t, Hotel{
+		ID:        "SjyX",
+		HotelName: "InterContinental",
+		Location:  Location{City: "Singapore"},
+		Amenities: Amenities{General: []string{"bar"}},
+		Images: map[string][]Image{
+			"site":  {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i1_m.jpg"}},
+			"rooms": {{Link: "https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i3_m.jpg"}},
+		},
+		Provenance: &HotelProvenance{
+			Fields: map[string]FieldSource{
+				MergeFieldHotelName: acme,
+				MergeFieldCity:      paperflies,
+			},
+			Elements: map[string]map[string]FieldSource{
+				MergeFieldDescription:           {},
+				ProvenanceFieldGeneralAmenities: {"bar": paperflies},
+				MergeFieldImages: {
+					"https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i1_m.jpg": acme,
+					"https://d2ey9sqrvkqdfs.cloudfront.net/Sjym/i3_m.jpg": paperflies,
+				},
+			},
+		},
+	}, stored)
+}
@@ -0,0 +1,99 @@
+package hotel_service
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Supplier is a single entry of the suppliers registry file.
+// Header values may reference environment variables (e.g. "Bearer ${ACME_TOKEN}") to keep secrets out of the file.
+type Supplier struct {
+	Name           string            `json:"name"`
+	URL            string            `json:"url"`
+	Priority       int               `json:"priority"`
+	Enabled        bool              `json:"enabled"`
+	TimeoutSeconds int               `json:"timeout_seconds,omitempty"`
+	Headers        map[string]string `json:"headers,omitempty"`
+	Adapter        string            `json:"adapter,omitempty"`
+	Mapping        string            `json:"mapping,omitempty"`
+	// MaxBodyBytes caps the size of the payload, zero uses defaultMaxBodyBytes.
+	MaxBodyBytes int64 `json:"max_body_bytes,omitempty"`
+}
+
+const defaultMaxBodyBytes int64 = 64 << 20
+
+func (s Supplier) timeout() time.Duration {
+	return time.Duration(s.TimeoutSeconds) * time.Second
+}
+
+func (s Supplier) maxBodyBytes() int64 {
+	if s.MaxBodyBytes > 0 {
+		return s.MaxBodyBytes
+	}
+	return defaultMaxBodyBytes
+}
+
+func (s Supplier) requestHeaders() map[string]string {
+	headers := make(map[string]string, len(s.Headers))
+	for key, value := range s.Headers {
+		headers[key] = os.ExpandEnv(value)
+	}
+	return headers
+}
+
+func (h *hotelServiceImpl) validateSuppliers(suppliers []Supplier) error {
+	var errs []string
+	names := make(map[string]bool)
+
+	for i, supplier := range suppliers {
+		if strings.TrimSpace(supplier.Name) == "" {
+			errs = append(errs, fmt.Sprintf("supplier #%d: missing name", i))
+		} else if names[supplier.Name] {
+			errs = append(errs, fmt.Sprintf("supplier %s: duplicate name", supplier.Name))
+		}
+		names[supplier.Name] = true
+
+		parsedURL, err := url.Parse(supplier.URL)
+		if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+			errs = append(errs, fmt.Sprintf("supplier %s: invalid url %q", supplier.Name, supplier.URL))
+		}
+		if supplier.Priority < 0 {
+			errs = append(errs, fmt.Sprintf("supplier %s: priority must not be negative", supplier.Name))
+		}
+		if supplier.TimeoutSeconds < 0 {
+			errs = append(errs, fmt.Sprintf("supplier %s: timeout_seconds must not be negative", supplier.Name))
+		}
+		if supplier.MaxBodyBytes < 0 {
+			errs = append(errs, fmt.Sprintf("supplier %s: max_body_bytes must not be negative", supplier.Name))
+		}
+		if supplier.Adapter != "" {
+			if _, ok := h.adapters[supplier.Adapter]; !ok {
+				errs = append(errs, fmt.Sprintf("supplier %s: unknown adapter %s", supplier.Name, supplier.Adapter))
+			}
+		}
+		if supplier.Mapping != "" && supplier.Mapping != defaultMappingName {
+			if _, ok := h.mappings[supplier.Mapping]; !ok {
+				errs = append(errs, fmt.Sprintf("supplier %s: unknown mapping %s", supplier.Name, supplier.Mapping))
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "\n"))
+	}
+	return nil
+}
+
+func enabledSuppliers(suppliers []Supplier) []Supplier {
+	enabled := make([]Supplier, 0, len(suppliers))
+	for _, supplier := range suppliers {
+		if supplier.Enabled {
+			enabled = append(enabled, supplier)
+		}
+	}
+	return enabled
+}
@@ -0,0 +1,290 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// SupplierAdapter fetches the payload of a supplier and normalizes each of its records into a Hotel.
+// Fetch passes the records to handle one at a time as they are decoded, so payloads are never held in memory.
+// Adapters are registered by name in Config.Adapters and referenced by the "adapter" field of a supplier.
+type SupplierAdapter interface {
+	Fetch(ctx context.Context, client HTTPClient, supplier Supplier, handle func(record json.RawMessage)) error
+	Normalize(record json.RawMessage) (Hotel, error)
+}
+
+// ErrIncompletePayload is returned by Fetch when the payload broke off, the records handled before are valid.
+var ErrIncompletePayload = errors.New("incomplete supplier payload")
+
+// DefaultSupplierAdapters returns the built-in adapters keyed by adapter name.
+func DefaultSupplierAdapters() map[string]SupplierAdapter {
+	return map[string]SupplierAdapter{
+		"acme":       &acmeAdapter{},
+		"patagonia":  &patagoniaAdapter{},
+		"paperflies": &paperfliesAdapter{},
+	}
+}
+
+func (h *hotelServiceImpl) adapterForSupplier(supplier Supplier) SupplierAdapter {
+	if adapter, ok := h.adapters[supplier.Adapter]; ok {
+		return adapter
+	}
+	return newMappingAdapter(h.mappingForSupplier(supplier), h.logger)
+}
+
+// httpFetcher streams the top level JSON array of a supplier, it is embedded by the built-in adapters.
+type httpFetcher struct{}
+
+func (httpFetcher) Fetch(ctx context.Context, client HTTPClient, supplier Supplier, handle func(record json.RawMessage)) error {
+	if supplier.timeout() > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, supplier.timeout())
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, supplier.URL, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request for %s: %w", supplier.Name, err)
+	}
+	for key, value := range supplier.requestHeaders() {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error fetching data from %s: %w", supplier.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("supplier %s returned %w: %d", supplier.Name, errUnexpectedCode, resp.StatusCode)
+	}
+	maxBodyBytes := supplier.maxBodyBytes()
+	if resp.ContentLength > maxBodyBytes {
+		return fmt.Errorf("supplier %s payload of %d bytes: %w of %d bytes", supplier.Name, resp.ContentLength, errPayloadTooLarge, maxBodyBytes)
+	}
+
+	body := &limitedReader{reader: resp.Body, remaining: maxBodyBytes}
+	decoder := json.NewDecoder(body)
+	token, err := decoder.Token()
+	if err != nil {
+		return fmt.Errorf("error decoding JSON from %s: %w", supplier.Name, body.wrap(err))
+	}
+	if delim, ok := token.(json.Delim); !ok || delim != '[' {
+		return fmt.Errorf("error decoding JSON from %s: payload is not an array", supplier.Name)
+	}
+	handled := 0
+	for decoder.More() {
+		var record json.RawMessage
+		if err := decoder.Decode(&record); err != nil {
+			err = body.wrap(err)
+			if handled > 0 && !errors.Is(err, errPayloadTooLarge) {
+				return fmt.Errorf("%w from %s after %d records: %v", ErrIncompletePayload, supplier.Name, handled, err)
+			}
+			return fmt.Errorf("error decoding JSON from %s: %w", supplier.Name, err)
+		}
+		handle(record)
+		handled++
+	}
+	if _, err := decoder.Token(); err != nil {
+		return fmt.Errorf("%w from %s after %d records: %v", ErrIncompletePayload, supplier.Name, handled, body.wrap(err))
+	}
+	// read the body to the end so the connection can be reused and the payload cached
+	io.Copy(io.Discard, body)
+	if body.exceeded {
+		return fmt.Errorf("error decoding JSON from %s: %w", supplier.Name, errPayloadTooLarge)
+	}
+	return nil
+}
+
+var errPayloadTooLarge = errors.New("payload exceeds the size limit")
+
+// limitedReader fails with errPayloadTooLarge once more than remaining bytes were read.
+type limitedReader struct {
+	reader    io.Reader
+	remaining int64
+	exceeded  bool
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if r.exceeded {
+		return 0, errPayloadTooLarge
+	}
+	if int64(len(p)) > r.remaining+1 {
+		p = p[:r.remaining+1]
+	}
+	n, err := r.reader.Read(p)
+	if int64(n) > r.remaining {
+		r.exceeded = true
+		return int(r.remaining), errPayloadTooLarge
+	}
+	r.remaining -= int64(n)
+	return n, err
+}
+
+// wrap replaces a decoding error caused by the size limit with errPayloadTooLarge.
+func (r *limitedReader) wrap(err error) error {
+	if r.exceeded {
+		return errPayloadTooLarge
+	}
+	return err
+}
+
+// mappingAdapter is the generic adapter, it probes the keys declared in a SupplierMapping.
+type mappingAdapter struct {
+	httpFetcher
+	mapping SupplierMapping
+	logger  logging.Logger
+}
+
+func newMappingAdapter(mapping SupplierMapping, logger logging.Logger) *mappingAdapter {
+	return &mappingAdapter{
+		mapping: mapping,
+		logger:  logger,
+	}
+}
+
+func (a *mappingAdapter) Normalize(record json.RawMessage) (Hotel, error) {
+	var hotel map[string]interface{}
+	err := json.Unmarshal(record, &hotel)
+	if err != nil {
+		return Hotel{}, fmt.Errorf("hotel data is not a JSON object: %w", err)
+	}
+
+	id := a.getHotelIdFromUpdatedData(hotel)
+	if id == "" {
+		return Hotel{}, fmt.Errorf("data is invalid, missing hotelId")
+	}
+	destinationId := a.getDestinationIdFromUpdatedData(hotel)
+	if destinationId == -1 {
+		return Hotel{}, fmt.Errorf("data is invalid, missing or invalid destination id for hotel %s", id)
+	}
+
+	return Hotel{
+		ID:               id,
+		DestinationID:    destinationId,
+		HotelName:        a.getHotelNameFromUpdatedData(hotel),
+		Location:         a.getLocationFromUpdatedData(hotel),
+		Description:      a.getHotelDescriptionFromUpdatedData(hotel),
+		Amenities:        a.getHotelAmenitiesFromUpdatedData(hotel),
+		Images:           a.getHotelImagesFromUpdatedData(hotel),
+		BookingCondition: a.getHotelBookingConditionFromUpdatedData(hotel),
+	}, nil
+}
+
+func (a *mappingAdapter) getHotelIdFromUpdatedData(hotel map[string]interface{}) string {
+	return a.getStringFromUpdatedData(hotel, a.mapping.ID, "Hotel id")
+}
+
+func (a *mappingAdapter) getDestinationIdFromUpdatedData(hotel map[string]interface{}) int {
+	var destinationId int
+	found := a.lookupCoerced(hotel, a.mapping.DestinationID, "Destination id", func(value interface{}) bool {
+		var ok bool
+		destinationId, ok = coerceInt(value)
+		return ok
+	})
+	if !found {
+		return -1
+	}
+	return destinationId
+}
+
+func (a *mappingAdapter) getHotelNameFromUpdatedData(hotel map[string]interface{}) string {
+	return a.getStringFromUpdatedData(hotel, a.mapping.HotelName, "Hotel name")
+}
+
+func (a *mappingAdapter) getLocationFromUpdatedData(hotel map[string]interface{}) Location {
+	return Location{
+		Lat:     a.getFloatFromUpdatedData(hotel, a.mapping.Location.Lat, "Latitude"),
+		Long:    a.getFloatFromUpdatedData(hotel, a.mapping.Location.Lng, "Longitude"),
+		Address: a.getStringFromUpdatedData(hotel, a.mapping.Location.Address, "Address"),
+		City:    a.getStringFromUpdatedData(hotel, a.mapping.Location.City, "City"),
+		Country: a.getStringFromUpdatedData(hotel, a.mapping.Location.Country, "Country"),
+	}
+}
+
+func (a *mappingAdapter) getHotelDescriptionFromUpdatedData(hotel map[string]interface{}) []string {
+	return optionalStringList(a.getStringFromUpdatedData(hotel, a.mapping.Description, "Description"))
+}
+
+func (a *mappingAdapter) getHotelBookingConditionFromUpdatedData(hotel map[string]interface{}) []string {
+	return a.getStringListFromUpdatedData(hotel, a.mapping.BookingConditions, "Booking Condition")
+}
+
+func (a *mappingAdapter) getHotelAmenitiesFromUpdatedData(hotel map[string]interface{}) Amenities {
+	var hotelAmenities Amenities
+
+	hotelAmenities.General = a.getStringListFromUpdatedData(hotel, a.mapping.Amenities.General, "Amenities")
+	hotelAmenities.Room = a.getStringListFromUpdatedData(hotel, a.mapping.Amenities.Room, "Amenities")
+
+	return hotelAmenities
+}
+
+func (a *mappingAdapter) getStringFromUpdatedData(hotel map[string]interface{}, paths []string, fieldName string) string {
+	var str string
+	a.lookupCoerced(hotel, paths, fieldName, func(value interface{}) bool {
+		var ok bool
+		str, ok = coerceString(value)
+		return ok
+	})
+	return str
+}
+
+func (a *mappingAdapter) getFloatFromUpdatedData(hotel map[string]interface{}, paths []string, fieldName string) float64 {
+	var number float64
+	a.lookupCoerced(hotel, paths, fieldName, func(value interface{}) bool {
+		var ok bool
+		number, ok = coerceFloat(value)
+		return ok
+	})
+	return number
+}
+
+// lookupCoerced passes the values of the paths to coerce in order until it accepts one, so a value of the wrong
+// type falls back to the next path. Scalars no path could coerce are logged, objects are other shapes of the data.
+func (a *mappingAdapter) lookupCoerced(hotel map[string]interface{}, paths []string, fieldName string, coerce func(value interface{}) bool) bool {
+	var unsupported []interface{}
+	for _, path := range paths {
+		value, ok := lookupFirstPath(hotel, []string{path})
+		if !ok {
+			continue
+		}
+		if coerce(value) {
+			return true
+		}
+		if _, isObject := value.(map[string]interface{}); !isObject {
+			unsupported = append(unsupported, value)
+		}
+	}
+	if len(unsupported) > 0 {
+		a.logger.Warn(fmt.Sprintf("%s data type not supported", fieldName), unsupported...)
+	}
+	return false
+}
+
+func (a *mappingAdapter) getStringListFromUpdatedData(hotel map[string]interface{}, paths []string, fieldName string) []string {
+	var values []string
+	a.lookupCoerced(hotel, paths, fieldName, func(value interface{}) bool {
+		var ok bool
+		values, ok = coerceStringList(value)
+		return ok
+	})
+	return values
+}
+
+func (a *mappingAdapter) getHotelImagesFromUpdatedData(hotel map[string]interface{}) map[string][]Image {
+	images, ok := lookupFirstPath(hotel, a.mapping.Images.Categories)
+	if !ok {
+		return nil
+	}
+	hotelImages, ok := coerceImages(images, a.mapping.Images.Link, a.mapping.Images.Description)
+	if !ok {
+		a.logger.Warn("Images data type not supported", images)
+	}
+	return hotelImages
+}
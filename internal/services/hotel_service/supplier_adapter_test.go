@@ -0,0 +1,175 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPFetcherFetch(t *testing.T) {
+	testCases := []struct {
+		description        string
+		payload            string
+		contentLength      int64
+		maxBodyBytes       int64
+		expectedRecords    []string
+		expectedErr        bool
+		expectedIncomplete bool
+	}{
+		{
+			description:     "stream every record",
+			payload:         `[{"Id": "SjyX"}, 12, {"Id": "f8c9"}]`,
+			expectedRecords: []string{`{"Id": "SjyX"}`, `12`, `{"Id": "f8c9"}`},
+		},
+		{
+			description:     "empty array",
+			payload:         ` [ ] `,
+			expectedRecords: nil,
+		},
+		{
+			description: "payload is not an array",
+			payload:     `{"hotels": []}`,
+			expectedErr: true,
+		},
+		{
+			description: "malformed first record",
+			payload:     `[{"Id": }]`,
+			expectedErr: true,
+		},
+		{
+			description:        "keep the records read before a malformed one",
+			payload:            `[{"Id": "SjyX"}, {"Id": "f8c9"}, {"Id": ]`,
+			expectedRecords:    []string{`{"Id": "SjyX"}`, `{"Id": "f8c9"}`},
+			expectedErr:        true,
+			expectedIncomplete: true,
+		},
+		{
+			description:        "keep the records of a truncated payload",
+			payload:            `[{"Id": "SjyX"}, {"Id": "f8c9"}`,
+			expectedRecords:    []string{`{"Id": "SjyX"}`, `{"Id": "f8c9"}`},
+			expectedErr:        true,
+			expectedIncomplete: true,
+		},
+		{
+			description:     "payload within the size limit",
+			payload:         `[{"Id": "SjyX"}]`,
+			maxBodyBytes:    16,
+			expectedRecords: []string{`{"Id": "SjyX"}`},
+		},
+		{
+			description:     "payload over the size limit",
+			payload:         `[{"Id": "SjyX"}, {"Id": "f8c9"}]`,
+			maxBodyBytes:    20,
+			expectedRecords: []string{`{"Id": "SjyX"}`},
+			expectedErr:     true,
+		},
+		{
+			description:   "content length over the size limit",
+			payload:       `[]`,
+			contentLength: 1 << 20,
+			maxBodyBytes:  1024,
+			expectedErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			mockClient := &MockHTTPClient{
+				Responses: map[string]*http.Response{
+					"https://example.com/hotels": {
+						StatusCode:    http.StatusOK,
+						ContentLength: tc.contentLength,
+						Body:          io.NopCloser(strings.NewReader(tc.payload)),
+					},
+				},
+			}
+			supplier := Supplier{Name: "example", URL: "https://example.com/hotels", MaxBodyBytes: tc.maxBodyBytes}
+
+			var records []string
+			err := httpFetcher{}.Fetch(context.Background(), mockClient, supplier, func(record json.RawMessage) {
+				records = append(records, string(record))
+			})
+			assert.Equal(t, tc.expectedRecords, records)
+			if tc.expectedErr {
+				assert.Error(t, err)
+				assert.Equal(t, tc.expectedIncomplete, errors.Is(err, ErrIncompletePayload))
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+// pipeHTTPClient serves a body written by the test while the fetcher reads it.
+type pipeHTTPClient struct {
+	body io.ReadCloser
+}
+
+func (m *pipeHTTPClient) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, ContentLength: -1, Body: m.body}, nil
+}
+
+func TestHTTPFetcherStreamsRecords(t *testing.T) {
+	reader, writer := io.Pipe()
+	handled := make(chan string)
+	go func() {
+		writer.Write([]byte(`[{"Id": "SjyX"},`))
+		// the first record must be handled before the rest of the payload is sent
+		select {
+		case <-handled:
+		case <-time.After(5 * time.Second):
+		}
+		writer.Write([]byte(`{"Id": "f8c9"}]`))
+		writer.Close()
+	}()
+
+	var records []string
+	err := httpFetcher{}.Fetch(context.Background(), &pipeHTTPClient{body: reader}, Supplier{Name: "example", URL: "https://example.com/hotels"}, func(record json.RawMessage) {
+		records = append(records, string(record))
+		if len(records) == 1 {
+			handled <- string(record)
+		}
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{`{"Id": "SjyX"}`, `{"Id": "f8c9"}`}, records)
+}
+
+func TestUpdateHotelsFromSuppliersIncompletePayload(t *testing.T) {
+	mockClient := &MockHTTPClient{
+		Responses: map[string]*http.Response{
+			"https://example.com/hotels": {
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(strings.NewReader(`[{"Id": "n3w1", "DestinationId": 5432, "Name": "New Hotel"}, {"Id": "SjyX", "Dest`)),
+			},
+		},
+	}
+	logger := logging.LogrusLogger()
+	wd, _ := os.Getwd()
+	repository := NewJSONHotelRepository(logger, copyTestDataFile(t, "test_sanitize_data.json"))
+	previousHotels, err := repository.Snapshot()
+	assert.Nil(t, err)
+	config := Config{
+		Repository:        repository,
+		SuppliersFilePath: filepath.Join(wd, "test_data", "test_suppliers.json"),
+		StalenessPolicy:   StalenessPolicy{MaxMissedUpdates: 1, Action: StalenessActionRemove},
+	}
+	hotelService := NewHotelService(logger, mockClient, context.Background(), config)
+
+	result, err := hotelService.UpdateHotelsFromSuppliers(UpdateOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, result.Warnings, 1)
+	assert.Equal(t, 1, result.Diff.HotelsAdded)
+	assert.Equal(t, 0, result.Diff.HotelsRemoved, "hotels missing from an incomplete payload are not stale")
+	hotels, err := repository.Snapshot()
+	assert.Nil(t, err)
+	assert.Len(t, hotels, len(previousHotels)+1)
+}
@@ -0,0 +1,145 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/utils"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+const defaultMappingName = "default"
+
+// SupplierMapping declares where each hotel field lives in a supplier payload.
+// Every field is a list of candidate JSON paths in dot notation (e.g. "location.address"),
+// the first path holding a value in a record is used.
+type SupplierMapping struct {
+	Name              string           `json:"name"`
+	ID                []string         `json:"id,omitempty"`
+	DestinationID     []string         `json:"destination_id,omitempty"`
+	HotelName         []string         `json:"hotel_name,omitempty"`
+	Location          LocationMapping  `json:"location,omitempty"`
+	Description       []string         `json:"description,omitempty"`
+	Amenities         AmenitiesMapping `json:"amenities,omitempty"`
+	Images            ImagesMapping    `json:"images,omitempty"`
+	BookingConditions []string         `json:"booking_conditions,omitempty"`
+}
+
+type LocationMapping struct {
+	Lat     []string `json:"lat,omitempty"`
+	Lng     []string `json:"lng,omitempty"`
+	Address []string `json:"address,omitempty"`
+	City    []string `json:"city,omitempty"`
+	Country []string `json:"country,omitempty"`
+}
+
+type AmenitiesMapping struct {
+	General []string `json:"general,omitempty"`
+	Room    []string `json:"room,omitempty"`
+}
+
+// ImagesMapping points to an object of image category -> list of images.
+// Link and Description are keys relative to each image object.
+type ImagesMapping struct {
+	Categories  []string `json:"categories,omitempty"`
+	Link        []string `json:"link,omitempty"`
+	Description []string `json:"description,omitempty"`
+}
+
+// defaultSupplierMapping probes every key seen so far across suppliers and is used
+// when a supplier does not reference a mapping file of its own.
+var defaultSupplierMapping = SupplierMapping{
+	Name:          defaultMappingName,
+	ID:            []string{"id", "Id", "hotel_id"},
+	DestinationID: []string{"destination_id", "DestinationId", "destination"},
+	HotelName:     []string{"Name", "name", "hotel_name"},
+	Location: LocationMapping{
+		Lat:     []string{"location.lat", "lat", "Latitude"},
+		Lng:     []string{"location.lng", "Longitude", "lng"},
+		Address: []string{"location.address", "Address", "address", "location"},
+		City:    []string{"location.city", "City", "city"},
+		Country: []string{"location.country", "country", "Country"},
+	},
+	Description: []string{"description", "Description", "info", "details"},
+	Amenities: AmenitiesMapping{
+		General: []string{"amenities.general", "amenities", "Facilities"},
+		Room:    []string{"amenities.room"},
+	},
+	Images: ImagesMapping{
+		Categories:  []string{"images", "Images"},
+		Link:        []string{"url", "link"},
+		Description: []string{"caption", "description"},
+	},
+	BookingConditions: []string{"booking_conditions"},
+}
+
+// LoadSupplierMappings reads every *.json file in dirPath as a SupplierMapping, keyed by mapping name.
+func LoadSupplierMappings(dirPath string) (map[string]SupplierMapping, error) {
+	filePaths, err := filepath.Glob(filepath.Join(dirPath, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	mappings := make(map[string]SupplierMapping)
+	for _, filePath := range filePaths {
+		data, err := utils.ReadJSONFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+		var mapping SupplierMapping
+		if err := json.Unmarshal(data, &mapping); err != nil {
+			return nil, fmt.Errorf("invalid mapping file %s: %w", filePath, err)
+		}
+		mapping.Name = strings.TrimSpace(mapping.Name)
+		if mapping.Name == "" {
+			mapping.Name = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+		}
+		if _, exists := mappings[mapping.Name]; exists {
+			return nil, fmt.Errorf("duplicate mapping name %s in %s", mapping.Name, filePath)
+		}
+		mappings[mapping.Name] = mapping
+	}
+
+	return mappings, nil
+}
+
+func (h *hotelServiceImpl) mappingForSupplier(supplier Supplier) SupplierMapping {
+	if mapping, ok := h.mappings[supplier.Mapping]; ok {
+		return mapping
+	}
+	if mapping, ok := h.mappings[defaultMappingName]; ok {
+		return mapping
+	}
+	return defaultSupplierMapping
+}
+
+func lookupPath(data map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = data
+	for _, key := range strings.Split(path, ".") {
+		container, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current, ok = container[key]
+		if !ok {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// lookupFirstPath returns the value of the first path holding a value, null and blank strings count as missing
+// so a supplier sending an empty nested field falls back to its flat one.
+func lookupFirstPath(data map[string]interface{}, paths []string) (interface{}, bool) {
+	for _, path := range paths {
+		val, ok := lookupPath(data, path)
+		if !ok || val == nil {
+			continue
+		}
+		if str, isString := val.(string); isString && strings.TrimSpace(str) == "" {
+			continue
+		}
+		return val, true
+	}
+	return nil, false
+}
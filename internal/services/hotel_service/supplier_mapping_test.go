@@ -0,0 +1,175 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSupplierMappings(t *testing.T) {
+	testCases := []struct {
+		description      string
+		dirPath          func() string
+		expectedErr      bool
+		expectedMappings []string
+	}{
+		{
+			description: "fail to parse invalid mapping file",
+			dirPath: func() string {
+				wd, _ := os.Getwd()
+				return filepath.Join(wd, "test_data", "invalid_mappings")
+			},
+			expectedErr: true,
+		},
+		{
+			description: "return no mappings for directory without mapping files",
+			dirPath: func() string {
+				return "invalid_dir"
+			},
+			expectedErr:      false,
+			expectedMappings: []string{},
+		},
+		{
+			description: "successfully load the bundled supplier mappings",
+			dirPath: func() string {
+				wd, _ := os.Getwd()
+				return filepath.Join(wd, "..", "..", "data", "mappings")
+			},
+			expectedErr:      false,
+			expectedMappings: []string{"acme", "patagonia", "paperflies"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			mappings, err := LoadSupplierMappings(tc.dirPath())
+			if tc.expectedErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				names := make([]string, 0, len(mappings))
+				for name := range mappings {
+					names = append(names, name)
+				}
+				assert.ElementsMatch(t, tc.expectedMappings, names)
+			}
+		})
+	}
+}
+
+func TestMappingAdapterNormalize(t *testing.T) {
+	wd, _ := os.Getwd()
+	mappings, err := LoadSupplierMappings(filepath.Join(wd, "test_data", "mappings"))
+	assert.Nil(t, err)
+
+	testCases := []struct {
+		description   string
+		supplier      Supplier
+		record        string
+		expectedErr   bool
+		expectedHotel Hotel
+	}{
+		{
+			description: "extract fields declared in the supplier mapping",
+			supplier:    Supplier{Name: "custom", Mapping: "custom"},
+			record: `{
+				"property": {"code": "abc1", "destination": 1122, "title": "Custom Hotel"},
+				"geo": {"latitude": 1.5, "longitude": 103.8, "country_name": "Singapore"},
+				"features": ["Pool", "Gym"]
+			}`,
+			expectedHotel: Hotel{
+				ID:            "abc1",
+				DestinationID: 1122,
+				HotelName:     "Custom Hotel",
+				Location:      Location{Lat: 1.5, Long: 103.8, Country: "Singapore"},
+				Amenities:     Amenities{General: []string{"Pool", "Gym"}},
+			},
+		},
+		{
+			description: "fall back to the default mapping for suppliers without mapping",
+			supplier:    Supplier{Name: "unknown"},
+			record: `{
+				"hotel_id": "xyz9",
+				"destination_id": 5432,
+				"hotel_name": "Default Hotel",
+				"location": {"address": "1 Default Road"}
+			}`,
+			expectedHotel: Hotel{
+				ID:            "xyz9",
+				DestinationID: 5432,
+				HotelName:     "Default Hotel",
+				Location:      Location{Address: "1 Default Road"},
+			},
+		},
+		{
+			description: "reject record without hotel id",
+			supplier:    Supplier{Name: "unknown"},
+			record:      `{"destination_id": 5432, "hotel_name": "Default Hotel"}`,
+			expectedErr: true,
+		},
+		{
+			description: "coerce strings, nulls and flat values of the default mapping",
+			supplier:    Supplier{Name: "unknown"},
+			record: `{
+				"hotel_id": 1234,
+				"destination_id": " 5432 ",
+				"hotel_name": "Default Hotel",
+				"location": "1 Default Road",
+				"lat": "1.264751",
+				"Longitude": 103.824006,
+				"City": null,
+				"amenities": {"general": null},
+				"Facilities": "Pool",
+				"booking_conditions": [null, "No pets", 42]
+			}`,
+			expectedHotel: Hotel{
+				ID:               "1234",
+				DestinationID:    5432,
+				HotelName:        "Default Hotel",
+				Location:         Location{Lat: 1.264751, Long: 103.824006, Address: "1 Default Road"},
+				Amenities:        Amenities{General: []string{"Pool"}},
+				BookingCondition: []string{"No pets", "42"},
+			},
+		},
+		{
+			description: "fall back to the next path when a value has the wrong type",
+			supplier:    Supplier{Name: "unknown"},
+			record: `{
+				"id": "", "Id": "xyz9",
+				"destination_id": "unknown", "destination": 5432,
+				"location": {"lat": "north", "address": null},
+				"Latitude": 1.5,
+				"Address": "1 Default Road"
+			}`,
+			expectedHotel: Hotel{
+				ID:            "xyz9",
+				DestinationID: 5432,
+				Location:      Location{Lat: 1.5, Address: "1 Default Road"},
+			},
+		},
+		{
+			description: "reject record with fractional destination id",
+			supplier:    Supplier{Name: "unknown"},
+			record:      `{"hotel_id": "xyz9", "destination_id": 5432.5, "hotel_name": "Default Hotel"}`,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			logger := logging.LogrusLogger()
+			hotelService := &hotelServiceImpl{logger: logger, ctx: context.Background(), mappings: mappings}
+			hotel, err := hotelService.adapterForSupplier(tc.supplier).Normalize(json.RawMessage(tc.record))
+			if tc.expectedErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expectedHotel, hotel)
+			}
+		})
+	}
+}
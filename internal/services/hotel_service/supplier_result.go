@@ -0,0 +1,128 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/utils"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	SupplierErrorCircuitOpen       = "circuit_open"
+	SupplierErrorTimeout           = "timeout"
+	SupplierErrorNetwork           = "network"
+	SupplierErrorHTTPStatus        = "http_status"
+	SupplierErrorPayloadTooLarge   = "payload_too_large"
+	SupplierErrorInvalidPayload    = "invalid_payload"
+	SupplierErrorIncompletePayload = "incomplete_payload"
+)
+
+var (
+	ErrUpdateAborted  = errors.New("update aborted")
+	errCircuitOpen    = errors.New("circuit breaker is open")
+	errUnexpectedCode = errors.New("unexpected status code")
+)
+
+// SupplierResult reports the fetch of a single supplier during an update. Status is SupplierStatusFetched or
+// SupplierStatusFailed, an incomplete payload is fetched with an ErrorCategory.
+type SupplierResult struct {
+	Supplier        string `json:"supplier"`
+	Status          string `json:"status"`
+	HTTPStatus      int    `json:"http_status,omitempty"`
+	Attempts        int    `json:"attempts,omitempty"`
+	DurationMs      int64  `json:"duration_ms"`
+	NotModified     bool   `json:"not_modified,omitempty"`
+	Records         int    `json:"records"`
+	RecordsRejected int    `json:"records_rejected"`
+	ErrorCategory   string `json:"error_category,omitempty"`
+	Error           string `json:"error,omitempty"`
+}
+
+// supplierFetch collects what the HTTP clients wrapping the requests of a supplier observed.
+type supplierFetch struct {
+	// notModified is set when the supplier answered 304 for a payload already applied.
+	notModified bool
+	// storedAt identifies the cached payload that was used, it is zero when nothing was cached.
+	storedAt time.Time
+	// statusCode is the status of the last response received from the supplier.
+	statusCode int
+	attempts   int
+}
+
+// supplierErrorCategory classifies the error of a supplier fetch.
+func supplierErrorCategory(err error, fetch *supplierFetch) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, errCircuitOpen):
+		return SupplierErrorCircuitOpen
+	case errors.Is(err, ErrIncompletePayload):
+		return SupplierErrorIncompletePayload
+	case errors.Is(err, errPayloadTooLarge):
+		return SupplierErrorPayloadTooLarge
+	case errors.Is(err, errUnexpectedCode):
+		return SupplierErrorHTTPStatus
+	case errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()):
+		return SupplierErrorTimeout
+	case fetch.statusCode == 0:
+		return SupplierErrorNetwork
+	default:
+		return SupplierErrorInvalidPayload
+	}
+}
+
+// PartialFailurePolicy decides when the failure of some suppliers aborts an update before anything is written.
+// An update always aborts when every supplier failed. RequireTopPriority requires the enabled suppliers with the
+// highest priority, RequiredSuppliers lists suppliers that must be fetched when enabled and MinSuccessfulSuppliers
+// is the number of suppliers that must be fetched. Incomplete payloads count as fetched.
+type PartialFailurePolicy struct {
+	RequireTopPriority     bool     `json:"require_top_priority"`
+	RequiredSuppliers      []string `json:"required_suppliers"`
+	MinSuccessfulSuppliers int      `json:"min_successful_suppliers"`
+}
+
+// check returns an error wrapping ErrUpdateAborted when the supplier results break the policy.
+func (p PartialFailurePolicy) check(suppliers []Supplier, results []SupplierResult) error {
+	updated := make(map[string]bool, len(suppliers))
+	for _, supplier := range suppliers {
+		updated[supplier.Name] = true
+	}
+	fetched := make(map[string]bool, len(results))
+	for _, result := range results {
+		if result.Status == SupplierStatusFetched {
+			fetched[result.Supplier] = true
+		}
+	}
+
+	var missing []string
+	required := append([]string{}, p.RequiredSuppliers...)
+	if p.RequireTopPriority {
+		topPriority := -1
+		for _, supplier := range suppliers {
+			if supplier.Priority > topPriority {
+				topPriority = supplier.Priority
+			}
+		}
+		for _, supplier := range suppliers {
+			if supplier.Priority == topPriority {
+				required = append(required, supplier.Name)
+			}
+		}
+	}
+	for _, name := range required {
+		if updated[name] && !fetched[name] && !utils.SliceContains(missing, name) {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return fmt.Errorf("%w: required suppliers failed: %s", ErrUpdateAborted, strings.Join(missing, ", "))
+	}
+	if len(fetched) < p.MinSuccessfulSuppliers {
+		return fmt.Errorf("%w: %d suppliers fetched, %d required", ErrUpdateAborted, len(fetched), p.MinSuccessfulSuppliers)
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"ascenda-loyalty-assignment/utils"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// timeoutError is a net.Error reporting a timeout.
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestSupplierErrorCategory(t *testing.T) {
+	testCases := []struct {
+		description      string
+		err              error
+		fetch            supplierFetch
+		expectedCategory string
+	}{
+		{
+			description:      "circuit breaker open",
+			err:              fmt.Errorf("supplier example skipped, its %w", errCircuitOpen),
+			expectedCategory: SupplierErrorCircuitOpen,
+		},
+		{
+			description:      "timeout",
+			err:              fmt.Errorf("request failed: %w", timeoutError{}),
+			expectedCategory: SupplierErrorTimeout,
+		},
+		{
+			description:      "context deadline",
+			err:              fmt.Errorf("request failed: %w", context.DeadlineExceeded),
+			expectedCategory: SupplierErrorTimeout,
+		},
+		{
+			description:      "no response",
+			err:              errors.New("connection refused"),
+			expectedCategory: SupplierErrorNetwork,
+		},
+		{
+			description:      "unexpected status code",
+			err:              fmt.Errorf("supplier example returned %w: 500", errUnexpectedCode),
+			fetch:            supplierFetch{statusCode: http.StatusInternalServerError},
+			expectedCategory: SupplierErrorHTTPStatus,
+		},
+		{
+			description:      "payload too large",
+			err:              fmt.Errorf("supplier example: %w", errPayloadTooLarge),
+			fetch:            supplierFetch{statusCode: http.StatusOK},
+			expectedCategory: SupplierErrorPayloadTooLarge,
+		},
+		{
+			description:      "incomplete payload",
+			err:              fmt.Errorf("%w from example after 2 records", ErrIncompletePayload),
+			fetch:            supplierFetch{statusCode: http.StatusOK},
+			expectedCategory: SupplierErrorIncompletePayload,
+		},
+		{
+			description:      "invalid payload",
+			err:              errors.New("supplier example payload is not an array"),
+			fetch:            supplierFetch{statusCode: http.StatusOK},
+			expectedCategory: SupplierErrorInvalidPayload,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expectedCategory, supplierErrorCategory(tc.err, &tc.fetch))
+		})
+	}
+}
+
+func TestPartialFailurePolicyCheck(t *testing.T) {
+	suppliers := []Supplier{
+		{Name: "primary", Priority: 2},
+		{Name: "backup", Priority: 1},
+		{Name: "other", Priority: 1},
+	}
+	testCases := []struct {
+		description   string
+		policy        PartialFailurePolicy
+		fetched       []string
+		expectedError string
+	}{
+		{
+			description: "no requirement",
+			fetched:     []string{"backup"},
+		},
+		{
+			description:   "top priority supplier failed",
+			policy:        PartialFailurePolicy{RequireTopPriority: true},
+			fetched:       []string{"backup", "other"},
+			expectedError: "update aborted: required suppliers failed: primary",
+		},
+		{
+			description: "top priority supplier fetched",
+			policy:      PartialFailurePolicy{RequireTopPriority: true},
+			fetched:     []string{"primary"},
+		},
+		{
+			description:   "required suppliers failed",
+			policy:        PartialFailurePolicy{RequiredSuppliers: []string{"other", "backup", "primary"}, RequireTopPriority: true},
+			fetched:       []string{"primary"},
+			expectedError: "update aborted: required suppliers failed: backup, other",
+		},
+		{
+			description: "required supplier not enabled",
+			policy:      PartialFailurePolicy{RequiredSuppliers: []string{"disabled"}},
+			fetched:     []string{"primary"},
+		},
+		{
+			description:   "not enough suppliers fetched",
+			policy:        PartialFailurePolicy{MinSuccessfulSuppliers: 2},
+			fetched:       []string{"primary"},
+			expectedError: "update aborted: 1 suppliers fetched, 2 required",
+		},
+		{
+			description: "enough suppliers fetched",
+			policy:      PartialFailurePolicy{MinSuccessfulSuppliers: 2},
+			fetched:     []string{"primary", "other"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var results []SupplierResult
+			for _, supplier := range suppliers {
+				status := SupplierStatusFailed
+				if utils.SliceContains(tc.fetched, supplier.Name) {
+					status = SupplierStatusFetched
+				}
+				results = append(results, SupplierResult{Supplier: supplier.Name, Status: status})
+			}
+			err := tc.policy.check(suppliers, results)
+			if tc.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expectedError)
+				assert.ErrorIs(t, err, ErrUpdateAborted)
+			}
+		})
+	}
+}
+
+func TestUpdateHotelsFromSuppliersResults(t *testing.T) {
+	testCases := []struct {
+		description     string
+		policy          PartialFailurePolicy
+		expectedAborted bool
+	}{
+		{
+			description: "partial failure is written",
+		},
+		{
+			description:     "partial failure aborts the update",
+			policy:          PartialFailurePolicy{RequireTopPriority: true},
+			expectedAborted: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			mockClient := &MockHTTPClient{
+				Responses: map[string]*http.Response{
+					"https://example.com/backup": {
+						StatusCode: http.StatusOK,
+						Body:       io.NopCloser(strings.NewReader(`[{"Id": "n3w1", "DestinationId": 5432, "Name": "New Hotel"}, {"Name": "No Id"}]`)),
+					},
+				},
+			}
+			logger := logging.LogrusLogger()
+			wd, _ := os.Getwd()
+			repository := NewJSONHotelRepository(logger, copyTestDataFile(t, "test_sanitize_data.json"))
+			previousHotels, err := repository.Snapshot()
+			assert.Nil(t, err)
+			quarantine := NewQuarantineStore(filepath.Join(t.TempDir(), "quarantine.json"))
+			config := Config{
+				Repository:           repository,
+				SuppliersFilePath:    filepath.Join(wd, "test_data", "test_partial_suppliers.json"),
+				PartialFailurePolicy: tc.policy,
+				Quarantine:           quarantine,
+			}
+			hotelService := NewHotelService(logger, mockClient, context.Background(), config)
+
+			result, err := hotelService.UpdateHotelsFromSuppliers(UpdateOptions{})
+			assert.Equal(t, []SupplierResult{
+				{
+					Supplier:        "backup",
+					Status:          SupplierStatusFetched,
+					HTTPStatus:      http.StatusOK,
+					Attempts:        1,
+					DurationMs:      result.Suppliers[0].DurationMs,
+					Records:         2,
+					RecordsRejected: 1,
+				},
+				{
+					Supplier:      "primary",
+					Status:        SupplierStatusFailed,
+					HTTPStatus:    http.StatusNotFound,
+					Attempts:      1,
+					DurationMs:    result.Suppliers[1].DurationMs,
+					ErrorCategory: SupplierErrorHTTPStatus,
+					Error:         "supplier primary returned unexpected status code: 404",
+				},
+			}, result.Suppliers)
+
+			hotels, readErr := repository.Snapshot()
+			assert.Nil(t, readErr)
+			quarantined, readErr := quarantine.List("")
+			assert.Nil(t, readErr)
+			if tc.expectedAborted {
+				assert.ErrorIs(t, err, ErrUpdateAborted)
+				assert.Len(t, hotels, len(previousHotels), "an aborted update writes nothing")
+				assert.Empty(t, quarantined, "an aborted update leaves the quarantine as it was")
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, 1, result.Diff.HotelsAdded)
+				assert.Len(t, hotels, len(previousHotels)+1)
+				assert.Len(t, quarantined, 1)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestUnmarshalSuppliers(t *testing.T) {
+	testCases := []struct {
+		description       string
+		suppliersFilePath func() string
+		expectedErr       bool
+		expectedSuppliers []Supplier
+	}{
+		{
+			description: "reject suppliers failing validation",
+			suppliersFilePath: func() string {
+				wd, _ := os.Getwd()
+				return filepath.Join(wd, "test_data", "invalid_suppliers.json")
+			},
+			expectedErr: true,
+		},
+		{
+			description: "successfully parse supplier registry",
+			suppliersFilePath: func() string {
+				wd, _ := os.Getwd()
+				return filepath.Join(wd, "test_data", "test_suppliers.json")
+			},
+			expectedErr: false,
+			expectedSuppliers: []Supplier{
+				{Name: "example", URL: "https://example.com/hotels", Priority: 1, Enabled: true},
+				{Name: "disabled", URL: "https://example.com/disabled", Priority: 2, Enabled: false},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			logger := logging.LogrusLogger()
+			hotelService := &hotelServiceImpl{logger: logger, ctx: context.Background()}
+			data, err := os.ReadFile(tc.suppliersFilePath())
+			assert.Nil(t, err)
+			suppliers, err := hotelService.unmarshalSuppliers(data)
+			if tc.expectedErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, tc.expectedSuppliers, suppliers)
+			}
+		})
+	}
+}
+
+func TestValidateSuppliersErrorMessage(t *testing.T) {
+	hotelService := &hotelServiceImpl{logger: logging.LogrusLogger(), ctx: context.Background()}
+	err := hotelService.validateSuppliers([]Supplier{{Name: "acme%20hotels", URL: "ftp://example.com/hotels%20list"}})
+	assert.EqualError(t, err, `supplier acme%20hotels: invalid url "ftp://example.com/hotels%20list"`)
+}
+
+func TestValidateSuppliersFile(t *testing.T) {
+	wd, _ := os.Getwd()
+	testCases := []struct {
+		description       string
+		suppliersFilePath string
+		expectedErr       bool
+	}{
+		{
+			description:       "fail on missing suppliers file",
+			suppliersFilePath: "invalid",
+			expectedErr:       true,
+		},
+		{
+			description:       "fail on suppliers failing validation",
+			suppliersFilePath: filepath.Join(wd, "test_data", "invalid_suppliers.json"),
+			expectedErr:       true,
+		},
+		{
+			description:       "accept valid suppliers file",
+			suppliersFilePath: filepath.Join(wd, "test_data", "test_suppliers.json"),
+		},
+		{
+			description:       "accept the shipped suppliers file",
+			suppliersFilePath: filepath.Join(wd, "..", "..", "data", "suppliers.json"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			mappings, err := LoadSupplierMappings(filepath.Join(wd, "..", "..", "data", "mappings"))
+			assert.Nil(t, err)
+			err = ValidateSuppliersFile(logging.LogrusLogger(), Config{SuppliersFilePath: tc.suppliersFilePath, Mappings: mappings})
+			if tc.expectedErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
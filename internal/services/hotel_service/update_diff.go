@@ -0,0 +1,80 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const updateDiffFilePrefix = "update-"
+
+var ErrUpdateNotFound = errors.New("update not found")
+
+// UpdateDiff is the change an update or a restore made to the catalog.
+type UpdateDiff struct {
+	ID        int         `json:"id"`
+	CreatedAt time.Time   `json:"created_at"`
+	Diff      CatalogDiff `json:"diff"`
+}
+
+// UpdateDiffStore keeps the diff of every update as a numbered JSON file of a directory. Diffs are kept apart
+// from the snapshots, so they survive the snapshot rotation and are recorded when versioning is disabled.
+type UpdateDiffStore struct {
+	mu      sync.Mutex
+	dirPath string
+}
+
+func NewUpdateDiffStore(dirPath string) (*UpdateDiffStore, error) {
+	if err := os.MkdirAll(dirPath, 0755); err != nil {
+		return nil, fmt.Errorf("error creating update diff directory %s: %w", dirPath, err)
+	}
+	return &UpdateDiffStore{dirPath: dirPath}, nil
+}
+
+// Save stores the diff as the next update, the id and creation time are filled in.
+func (s *UpdateDiffStore) Save(diff CatalogDiff) (UpdateDiff, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, err := numberedFileIds(s.dirPath, updateDiffFilePrefix)
+	if err != nil {
+		return UpdateDiff{}, fmt.Errorf("error listing update diffs in %s: %w", s.dirPath, err)
+	}
+	nextID := 1
+	if len(ids) > 0 {
+		nextID = ids[len(ids)-1] + 1
+	}
+	update := UpdateDiff{ID: nextID, CreatedAt: time.Now().UTC(), Diff: diff}
+	if err := utils.WriteJSONFile(s.filePath(nextID), update); err != nil {
+		return UpdateDiff{}, fmt.Errorf("error writing update diff %d: %w", nextID, err)
+	}
+	return update, nil
+}
+
+// Load returns the diff of an update, or ErrUpdateNotFound.
+func (s *UpdateDiffStore) Load(id int) (UpdateDiff, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := utils.ReadJSONFile(s.filePath(id))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return UpdateDiff{}, fmt.Errorf("%w: %d", ErrUpdateNotFound, id)
+		}
+		return UpdateDiff{}, fmt.Errorf("error reading update diff %d: %w", id, err)
+	}
+	var update UpdateDiff
+	if err := json.Unmarshal(data, &update); err != nil {
+		return UpdateDiff{}, fmt.Errorf("error decoding update diff %d: %w", id, err)
+	}
+	return update, nil
+}
+
+func (s *UpdateDiffStore) filePath(id int) string {
+	return filepath.Join(s.dirPath, fmt.Sprintf("%s%d.json", updateDiffFilePrefix, id))
+}
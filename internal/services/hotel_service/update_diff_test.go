@@ -0,0 +1,73 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestUpdateDiffStore(t *testing.T) {
+	store, err := NewUpdateDiffStore(filepath.Join(t.TempDir(), "update_diffs"))
+	assert.Nil(t, err)
+
+	diffs := []CatalogDiff{
+		{Added: []string{"iJhz"}, Removed: []string{}, Changed: []HotelChange{}},
+		{Added: []string{}, Removed: []string{"iJhz"}, Changed: []HotelChange{}},
+	}
+	for i, diff := range diffs {
+		update, err := store.Save(diff)
+		assert.Nil(t, err)
+		assert.Equal(t, i+1, update.ID)
+	}
+
+	update, err := store.Load(2)
+	assert.Nil(t, err)
+	assert.Equal(t, diffs[1], update.Diff)
+
+	_, err = store.Load(3)
+	assert.ErrorIs(t, err, ErrUpdateNotFound)
+}
+
+func TestUpdateHotelsFromSuppliersRecordsDiff(t *testing.T) {
+	mockClient := &MockHTTPClient{Responses: map[string]*http.Response{}}
+	logger := logging.LogrusLogger()
+	wd, _ := os.Getwd()
+	updateDiffs, err := NewUpdateDiffStore(filepath.Join(t.TempDir(), "update_diffs"))
+	assert.Nil(t, err)
+	// versioning is disabled, the diffs are still recorded
+	hotelService := NewHotelService(logger, mockClient, context.Background(), Config{
+		Repository:        NewJSONHotelRepository(logger, copyTestDataFile(t, "test_sanitize_data.json")),
+		SuppliersFilePath: filepath.Join(wd, "test_data", "test_suppliers.json"),
+		Adapters:          map[string]SupplierAdapter{"": &acmeAdapter{}},
+		UpdateDiffs:       updateDiffs,
+	})
+	update := func() UpdateResult {
+		mockClient.Responses["https://example.com/hotels"] = &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       io.NopCloser(strings.NewReader(`[{"Id": "n3w1", "DestinationId": 5432, "Name": "New Hotel"}]`)),
+		}
+		result, err := hotelService.UpdateHotelsFromSuppliers(UpdateOptions{})
+		assert.Nil(t, err)
+		return result
+	}
+
+	result := update()
+	assert.Equal(t, 1, result.UpdateID)
+	assert.Zero(t, result.SnapshotID)
+	diff, err := hotelService.GetUpdateDiff(result.UpdateID)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"n3w1"}, diff.Added)
+
+	// an update that changed nothing records no diff
+	result = update()
+	assert.Equal(t, DiffSummary{}, result.Diff)
+	assert.Zero(t, result.UpdateID)
+	_, err = hotelService.GetUpdateDiff(2)
+	assert.ErrorIs(t, err, ErrUpdateNotFound)
+}
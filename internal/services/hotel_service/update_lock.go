@@ -0,0 +1,45 @@
+package hotel_service
+
+import (
+	"fmt"
+	"os"
+	"sync"
+)
+
+// UpdateLock serializes the read-merge-write cycles of UpdateHotelsFromSuppliers: a mutex for the requests
+// of this process and, when a lock file is set, an exclusive lock on it for other processes.
+type UpdateLock struct {
+	mu           sync.Mutex
+	lockFilePath string
+}
+
+// processUpdateLock is used by services configured without an UpdateLock.
+var processUpdateLock = &UpdateLock{}
+
+func NewUpdateLock(lockFilePath string) *UpdateLock {
+	return &UpdateLock{lockFilePath: lockFilePath}
+}
+
+// Lock blocks until the update cycle can run, the returned function releases the lock.
+func (l *UpdateLock) Lock() (func(), error) {
+	l.mu.Lock()
+	if l.lockFilePath == "" {
+		return l.mu.Unlock, nil
+	}
+
+	file, err := os.OpenFile(l.lockFilePath, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		l.mu.Unlock()
+		return nil, fmt.Errorf("error opening lock file %s: %w", l.lockFilePath, err)
+	}
+	if err = lockFile(file); err != nil {
+		file.Close()
+		l.mu.Unlock()
+		return nil, fmt.Errorf("error locking %s: %w", l.lockFilePath, err)
+	}
+	return func() {
+		unlockFile(file)
+		file.Close()
+		l.mu.Unlock()
+	}, nil
+}
@@ -0,0 +1,36 @@
+package hotel_service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestUpdateLock(t *testing.T) {
+	lock := NewUpdateLock(filepath.Join(t.TempDir(), "update.lock"))
+
+	var running, overlaps int32
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := lock.Lock()
+			if !assert.Nil(t, err) {
+				return
+			}
+			defer unlock()
+			if atomic.AddInt32(&running, 1) > 1 {
+				atomic.AddInt32(&overlaps, 1)
+			}
+			time.Sleep(5 * time.Millisecond)
+			atomic.AddInt32(&running, -1)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(0), overlaps)
+}
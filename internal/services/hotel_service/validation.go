@@ -0,0 +1,258 @@
+package hotel_service
+
+import (
+	"ascenda-loyalty-assignment/utils"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	ValidationRuleInvalidRecord = "invalid_record"
+	ValidationRuleRequired      = "required"
+	ValidationRuleRange         = "range"
+	ValidationRuleFormat        = "format"
+	ValidationRuleURL           = "url"
+	ValidationRuleMaxLength     = "max_length"
+)
+
+const (
+	ValidationFieldID               = "id"
+	ValidationFieldDestinationID    = "destination_id"
+	ValidationFieldName             = "name"
+	ValidationFieldLat              = "lat"
+	ValidationFieldLng              = "lng"
+	ValidationFieldAddress          = "address"
+	ValidationFieldCity             = "city"
+	ValidationFieldCountry          = "country"
+	ValidationFieldDescription      = "description"
+	ValidationFieldAmenity          = "amenity"
+	ValidationFieldImageLink        = "image_link"
+	ValidationFieldImageDescription = "image_description"
+	ValidationFieldBookingCondition = "booking_condition"
+)
+
+// requirableFields are the fields RequiredFields may list, id and destination_id are always required.
+var requirableFields = []string{
+	ValidationFieldID, ValidationFieldDestinationID, ValidationFieldName,
+	ValidationFieldAddress, ValidationFieldCity, ValidationFieldCountry,
+}
+
+// lengthLimitedFields are the fields MaxLengths may limit.
+var lengthLimitedFields = []string{
+	ValidationFieldID, ValidationFieldName, ValidationFieldAddress, ValidationFieldCity, ValidationFieldCountry,
+	ValidationFieldDescription, ValidationFieldAmenity, ValidationFieldImageLink, ValidationFieldImageDescription,
+	ValidationFieldBookingCondition,
+}
+
+// ValidationRules are checked on every normalized supplier record, a record whose scalar fields break one of them
+// is quarantined instead of merged, while the invalid elements of its list fields are dropped one by one.
+// Coordinates must be valid latitudes and longitudes and image links absolute http(s) URLs, whatever the rules.
+// IDPattern is a regular expression the hotel ids must fully match and MaxLengths limits the length in
+// characters of the string fields, each element of a list field counts on its own.
+type ValidationRules struct {
+	RequiredFields []string       `json:"required_fields"`
+	IDPattern      string         `json:"id_pattern"`
+	MaxLengths     map[string]int `json:"max_lengths"`
+	idPattern      *regexp.Regexp
+}
+
+// RecordViolation is a rule a supplier record breaks.
+type RecordViolation struct {
+	Field   string `json:"field,omitempty"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func LoadValidationRules(filePath string) (ValidationRules, error) {
+	data, err := utils.ReadJSONFile(filePath)
+	if err != nil {
+		return ValidationRules{}, err
+	}
+	var rules ValidationRules
+	err = json.Unmarshal(data, &rules)
+	if err != nil {
+		return ValidationRules{}, fmt.Errorf("invalid validation rules file %s: %w", filePath, err)
+	}
+	for _, field := range rules.RequiredFields {
+		if !utils.SliceContains(requirableFields, field) {
+			return ValidationRules{}, fmt.Errorf("field %q cannot be required", field)
+		}
+	}
+	for field, maxLength := range rules.MaxLengths {
+		if !utils.SliceContains(lengthLimitedFields, field) {
+			return ValidationRules{}, fmt.Errorf("unknown max length field %q", field)
+		}
+		if maxLength < 0 {
+			return ValidationRules{}, fmt.Errorf("max length of %s must not be negative", field)
+		}
+	}
+	if rules.IDPattern != "" {
+		rules.idPattern, err = regexp.Compile("^(?:" + rules.IDPattern + ")$")
+		if err != nil {
+			return ValidationRules{}, fmt.Errorf("invalid id_pattern: %w", err)
+		}
+	}
+	return rules, nil
+}
+
+// validate returns the violations of the scalar fields of a normalized hotel, sorted by field.
+func (r ValidationRules) validate(hotel Hotel) []RecordViolation {
+	var violations []RecordViolation
+	addViolation := func(field string, rule string, message string, args ...interface{}) {
+		violations = append(violations, RecordViolation{Field: field, Rule: rule, Message: fmt.Sprintf(message, args...)})
+	}
+
+	values := map[string]string{
+		ValidationFieldID:      hotel.ID,
+		ValidationFieldName:    hotel.HotelName,
+		ValidationFieldAddress: hotel.Location.Address,
+		ValidationFieldCity:    hotel.Location.City,
+		ValidationFieldCountry: hotel.Location.Country,
+	}
+	if hotel.DestinationID != 0 {
+		values[ValidationFieldDestinationID] = fmt.Sprintf("%d", hotel.DestinationID)
+	}
+	for _, field := range append([]string{ValidationFieldID, ValidationFieldDestinationID}, r.RequiredFields...) {
+		if strings.TrimSpace(values[field]) == "" && !hasViolation(violations, field) {
+			addViolation(field, ValidationRuleRequired, "%s is required", field)
+		}
+	}
+	if hotel.DestinationID < 0 {
+		addViolation(ValidationFieldDestinationID, ValidationRuleRange, "destination id %d must be positive", hotel.DestinationID)
+	}
+
+	if hotel.Location.Lat < -90 || hotel.Location.Lat > 90 {
+		addViolation(ValidationFieldLat, ValidationRuleRange, "latitude %v is out of range [-90, 90]", hotel.Location.Lat)
+	}
+	if hotel.Location.Long < -180 || hotel.Location.Long > 180 {
+		addViolation(ValidationFieldLng, ValidationRuleRange, "longitude %v is out of range [-180, 180]", hotel.Location.Long)
+	}
+
+	if idPattern := r.compiledIDPattern(); idPattern != nil && hotel.ID != "" && !idPattern.MatchString(hotel.ID) {
+		addViolation(ValidationFieldID, ValidationRuleFormat, "id %q does not match %s", hotel.ID, r.IDPattern)
+	}
+
+	lengthChecks := map[string]string{
+		ValidationFieldID:      hotel.ID,
+		ValidationFieldName:    hotel.HotelName,
+		ValidationFieldAddress: hotel.Location.Address,
+		ValidationFieldCity:    hotel.Location.City,
+		ValidationFieldCountry: hotel.Location.Country,
+	}
+	for field, value := range lengthChecks {
+		if violation, ok := r.lengthViolation(field, value); ok {
+			violations = append(violations, violation)
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Field < violations[j].Field
+	})
+	return violations
+}
+
+// dropInvalidElements removes the list elements of a normalized hotel breaking a rule: descriptions, amenities
+// and booking conditions over their max length, and images with an invalid link or a too long description.
+// It returns the violations of the dropped elements, sorted by field.
+func (r ValidationRules) dropInvalidElements(hotel *Hotel) []RecordViolation {
+	var violations []RecordViolation
+	keepValid := func(field string, values []string) []string {
+		var kept []string
+		for _, value := range values {
+			if violation, ok := r.lengthViolation(field, value); ok {
+				violations = append(violations, violation)
+				continue
+			}
+			kept = append(kept, value)
+		}
+		return kept
+	}
+	hotel.Description = keepValid(ValidationFieldDescription, hotel.Description)
+	hotel.Amenities.General = keepValid(ValidationFieldAmenity, hotel.Amenities.General)
+	hotel.Amenities.Room = keepValid(ValidationFieldAmenity, hotel.Amenities.Room)
+	hotel.BookingCondition = keepValid(ValidationFieldBookingCondition, hotel.BookingCondition)
+
+	var images map[string][]Image
+	for _, category := range sortedImageCategories(hotel.Images) {
+		for _, image := range hotel.Images[category] {
+			if image.Link != "" && !isAbsoluteHTTPURL(image.Link) {
+				violations = append(violations, RecordViolation{
+					Field:   ValidationFieldImageLink,
+					Rule:    ValidationRuleURL,
+					Message: fmt.Sprintf("image link %q is not an absolute http(s) URL", image.Link),
+				})
+				continue
+			}
+			if violation, ok := r.lengthViolation(ValidationFieldImageLink, image.Link); ok {
+				violations = append(violations, violation)
+				continue
+			}
+			if violation, ok := r.lengthViolation(ValidationFieldImageDescription, image.Description); ok {
+				violations = append(violations, violation)
+				continue
+			}
+			if images == nil {
+				images = make(map[string][]Image)
+			}
+			images[category] = append(images[category], image)
+		}
+	}
+	hotel.Images = images
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		return violations[i].Field < violations[j].Field
+	})
+	return violations
+}
+
+// lengthViolation checks a value against the max length of its field.
+func (r ValidationRules) lengthViolation(field string, value string) (RecordViolation, bool) {
+	maxLength := r.MaxLengths[field]
+	if maxLength <= 0 {
+		return RecordViolation{}, false
+	}
+	length := utf8.RuneCountInString(value)
+	if length <= maxLength {
+		return RecordViolation{}, false
+	}
+	return RecordViolation{
+		Field:   field,
+		Rule:    ValidationRuleMaxLength,
+		Message: fmt.Sprintf("%s of %d characters exceeds %d characters", field, length, maxLength),
+	}, true
+}
+
+// compiledIDPattern returns the pattern compiled by LoadValidationRules, rules built in code are compiled here.
+func (r ValidationRules) compiledIDPattern() *regexp.Regexp {
+	if r.idPattern != nil || r.IDPattern == "" {
+		return r.idPattern
+	}
+	idPattern, err := regexp.Compile("^(?:" + r.IDPattern + ")$")
+	if err != nil {
+		return nil
+	}
+	return idPattern
+}
+
+func hasViolation(violations []RecordViolation, field string) bool {
+	for _, violation := range violations {
+		if violation.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+func isAbsoluteHTTPURL(link string) bool {
+	parsed, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	scheme := strings.ToLower(parsed.Scheme)
+	return scheme == "http" || scheme == "https"
+}
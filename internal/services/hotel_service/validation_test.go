@@ -0,0 +1,180 @@
+package hotel_service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadValidationRules(t *testing.T) {
+	testCases := []struct {
+		description   string
+		rulesFilePath func() string
+		expectedErr   bool
+	}{
+		{
+			description: "fail to read missing rules file",
+			rulesFilePath: func() string {
+				return "invalid_path"
+			},
+			expectedErr: true,
+		},
+		{
+			description: "reject invalid id pattern",
+			rulesFilePath: func() string {
+				wd, _ := os.Getwd()
+				return filepath.Join(wd, "test_data", "invalid_validation_rules.json")
+			},
+			expectedErr: true,
+		},
+		{
+			description: "successfully load the bundled validation rules",
+			rulesFilePath: func() string {
+				wd, _ := os.Getwd()
+				return filepath.Join(wd, "..", "..", "data", "validation_rules.json")
+			},
+			expectedErr: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			rules, err := LoadValidationRules(tc.rulesFilePath())
+			if tc.expectedErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.NotNil(t, rules.idPattern)
+			}
+		})
+	}
+}
+
+func TestValidationRulesValidate(t *testing.T) {
+	rules := ValidationRules{
+		RequiredFields: []string{ValidationFieldName},
+		IDPattern:      "[A-Za-z0-9]{4}",
+		MaxLengths:     map[string]int{ValidationFieldName: 10, ValidationFieldAmenity: 5},
+	}
+	validHotel := func() Hotel {
+		return Hotel{
+			ID:            "SjyX",
+			DestinationID: 5432,
+			HotelName:     "Villa",
+			Location:      Location{Lat: 1.264751, Long: 103.824006},
+			Amenities:     Amenities{General: []string{"pool"}},
+			Images:        map[string][]Image{"rooms": {{Link: "https://example.com/1.jpg"}}},
+		}
+	}
+
+	testCases := []struct {
+		description        string
+		hotel              func() Hotel
+		expectedViolations []RecordViolation
+	}{
+		{
+			description: "valid hotel",
+			hotel:       validHotel,
+		},
+		{
+			description: "missing required fields",
+			hotel: func() Hotel {
+				return Hotel{ID: " "}
+			},
+			expectedViolations: []RecordViolation{
+				{Field: ValidationFieldDestinationID, Rule: ValidationRuleRequired, Message: "destination_id is required"},
+				{Field: ValidationFieldID, Rule: ValidationRuleRequired, Message: "id is required"},
+				{Field: ValidationFieldID, Rule: ValidationRuleFormat, Message: `id " " does not match [A-Za-z0-9]{4}`},
+				{Field: ValidationFieldName, Rule: ValidationRuleRequired, Message: "name is required"},
+			},
+		},
+		{
+			description: "coordinates out of range",
+			hotel: func() Hotel {
+				hotel := validHotel()
+				hotel.Location.Lat, hotel.Location.Long = 91, -180.5
+				return hotel
+			},
+			expectedViolations: []RecordViolation{
+				{Field: ValidationFieldLat, Rule: ValidationRuleRange, Message: "latitude 91 is out of range [-90, 90]"},
+				{Field: ValidationFieldLng, Rule: ValidationRuleRange, Message: "longitude -180.5 is out of range [-180, 180]"},
+			},
+		},
+		{
+			description: "id format",
+			hotel: func() Hotel {
+				hotel := validHotel()
+				hotel.ID = "SjyX/1"
+				return hotel
+			},
+			expectedViolations: []RecordViolation{
+				{Field: ValidationFieldID, Rule: ValidationRuleFormat, Message: `id "SjyX/1" does not match [A-Za-z0-9]{4}`},
+			},
+		},
+		{
+			description: "strings over their max length",
+			hotel: func() Hotel {
+				hotel := validHotel()
+				hotel.HotelName = "Beach Villa"
+				return hotel
+			},
+			expectedViolations: []RecordViolation{
+				{Field: ValidationFieldName, Rule: ValidationRuleMaxLength, Message: "name of 11 characters exceeds 10 characters"},
+			},
+		},
+		{
+			description: "invalid list elements do not reject the record",
+			hotel: func() Hotel {
+				hotel := validHotel()
+				hotel.Amenities.Room = []string{"minibar"}
+				hotel.Images["site"] = []Image{{Link: "/images/2.jpg"}}
+				return hotel
+			},
+		},
+		{
+			description: "max length counts characters",
+			hotel: func() Hotel {
+				hotel := validHotel()
+				hotel.HotelName = strings.Repeat("é", 10)
+				return hotel
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			assert.Equal(t, tc.expectedViolations, rules.validate(tc.hotel()))
+		})
+	}
+}
+
+func TestValidationRulesDropInvalidElements(t *testing.T) {
+	rules := ValidationRules{
+		MaxLengths: map[string]int{ValidationFieldAmenity: 5, ValidationFieldBookingCondition: 10, ValidationFieldImageDescription: 6},
+	}
+	hotel := Hotel{
+		ID:               "SjyX",
+		DestinationID:    5432,
+		Amenities:        Amenities{General: []string{"pool", "business center"}, Room: []string{"minibar", "tv"}},
+		BookingCondition: []string{"No pets", "Check-in after 3pm"},
+		Images: map[string][]Image{
+			"rooms": {{Link: "https://example.com/1.jpg", Description: "Room"}, {Link: "/images/2.jpg"}},
+			"site":  {{Link: "ftp://example.com/3.jpg"}, {Link: "https://example.com/4.jpg", Description: "Hotel front"}},
+		},
+	}
+
+	violations := rules.dropInvalidElements(&hotel)
+	assert.Equal(t, []RecordViolation{
+		{Field: ValidationFieldAmenity, Rule: ValidationRuleMaxLength, Message: "amenity of 15 characters exceeds 5 characters"},
+		{Field: ValidationFieldAmenity, Rule: ValidationRuleMaxLength, Message: "amenity of 7 characters exceeds 5 characters"},
+		{Field: ValidationFieldBookingCondition, Rule: ValidationRuleMaxLength, Message: "booking_condition of 18 characters exceeds 10 characters"},
+		{Field: ValidationFieldImageDescription, Rule: ValidationRuleMaxLength, Message: "image_description of 11 characters exceeds 6 characters"},
+		{Field: ValidationFieldImageLink, Rule: ValidationRuleURL, Message: `image link "/images/2.jpg" is not an absolute http(s) URL`},
+		{Field: ValidationFieldImageLink, Rule: ValidationRuleURL, Message: `image link "ftp://example.com/3.jpg" is not an absolute http(s) URL`},
+	}, violations)
+	assert.Equal(t, Amenities{General: []string{"pool"}, Room: []string{"tv"}}, hotel.Amenities)
+	assert.Equal(t, []string{"No pets"}, hotel.BookingCondition)
+	assert.Equal(t, map[string][]Image{"rooms": {{Link: "https://example.com/1.jpg", Description: "Room"}}}, hotel.Images)
+}
@@ -0,0 +1,202 @@
+package update_jobs
+
+import (
+	"ascenda-loyalty-assignment/internal/services/hotel_service"
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+type JobStatus string
+
+const (
+	JobStatusQueued    JobStatus = "queued"
+	JobStatusRunning   JobStatus = "running"
+	JobStatusSucceeded JobStatus = "succeeded"
+	JobStatusFailed    JobStatus = "failed"
+)
+
+var ErrQueueFull = errors.New("update job queue is full")
+
+// RunFunc runs a single update with the given options.
+type RunFunc func(ctx context.Context, options hotel_service.UpdateOptions) (hotel_service.UpdateResult, error)
+
+// Job is an update requested through the API, Suppliers holds the fetch progress of each supplier.
+type Job struct {
+	ID         string                           `json:"id"`
+	Status     JobStatus                        `json:"status"`
+	DryRun     bool                             `json:"dry_run,omitempty"`
+	CreatedAt  time.Time                        `json:"created_at"`
+	StartedAt  *time.Time                       `json:"started_at,omitempty"`
+	FinishedAt *time.Time                       `json:"finished_at,omitempty"`
+	Suppliers  []hotel_service.SupplierProgress `json:"suppliers"`
+	Error      string                           `json:"error,omitempty"`
+	Result     *hotel_service.UpdateResult      `json:"result,omitempty"`
+}
+
+// Manager runs the update jobs one at a time in the background and keeps the most recent ones in memory.
+type Manager struct {
+	logger  logging.Logger
+	run     RunFunc
+	queue   chan string
+	maxJobs int
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+	// order holds the job ids from the oldest to the most recent.
+	order []string
+}
+
+func NewManager(logger logging.Logger, run RunFunc, queueSize int, maxJobs int) *Manager {
+	return &Manager{
+		logger:  logger,
+		run:     run,
+		queue:   make(chan string, queueSize),
+		maxJobs: maxJobs,
+		jobs:    make(map[string]*Job),
+	}
+}
+
+// Start runs the queued jobs until ctx is done.
+func (m *Manager) Start(ctx context.Context) {
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case id := <-m.queue:
+				m.runJob(ctx, id)
+			}
+		}
+	}()
+}
+
+// Enqueue adds an update job to the queue, it fails with ErrQueueFull when too many jobs are waiting.
+func (m *Manager) Enqueue(options hotel_service.UpdateOptions) (Job, error) {
+	id, err := newJobID()
+	if err != nil {
+		return Job{}, err
+	}
+	job := &Job{
+		ID:        id,
+		Status:    JobStatusQueued,
+		DryRun:    options.DryRun,
+		CreatedAt: time.Now().UTC(),
+		Suppliers: []hotel_service.SupplierProgress{},
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	select {
+	case m.queue <- id:
+	default:
+		return Job{}, ErrQueueFull
+	}
+	m.jobs[id] = job
+	m.order = append(m.order, id)
+	m.evict()
+	return copyJob(job), nil
+}
+
+func (m *Manager) Get(id string) (Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, exists := m.jobs[id]
+	if !exists {
+		return Job{}, false
+	}
+	return copyJob(job), true
+}
+
+func (m *Manager) runJob(ctx context.Context, id string) {
+	m.mu.Lock()
+	job, exists := m.jobs[id]
+	if !exists {
+		m.mu.Unlock()
+		return
+	}
+	startedAt := time.Now().UTC()
+	job.Status = JobStatusRunning
+	job.StartedAt = &startedAt
+	options := hotel_service.UpdateOptions{DryRun: job.DryRun, Progress: func(progress hotel_service.SupplierProgress) {
+		m.setSupplierProgress(id, progress)
+	}}
+	m.mu.Unlock()
+
+	result, err := m.run(ctx, options)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	finishedAt := time.Now().UTC()
+	job.FinishedAt = &finishedAt
+	if err != nil {
+		m.logger.Error(fmt.Sprintf("Update job %s failed", id), err)
+		job.Status = JobStatusFailed
+		job.Error = err.Error()
+		// a failed update still reports the outcome of each supplier
+		if len(result.Suppliers) > 0 {
+			job.Result = &result
+		}
+		return
+	}
+	job.Status = JobStatusSucceeded
+	job.Result = &result
+}
+
+func (m *Manager) setSupplierProgress(id string, progress hotel_service.SupplierProgress) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, exists := m.jobs[id]
+	if !exists {
+		return
+	}
+	for i := range job.Suppliers {
+		if job.Suppliers[i].Supplier == progress.Supplier {
+			job.Suppliers[i] = progress
+			return
+		}
+	}
+	job.Suppliers = append(job.Suppliers, progress)
+	sort.Slice(job.Suppliers, func(i, j int) bool {
+		return job.Suppliers[i].Supplier < job.Suppliers[j].Supplier
+	})
+}
+
+// evict drops the oldest finished jobs beyond maxJobs, queued and running jobs are always kept.
+func (m *Manager) evict() {
+	excess := len(m.order) - m.maxJobs
+	if m.maxJobs <= 0 || excess <= 0 {
+		return
+	}
+	kept := m.order[:0]
+	for _, id := range m.order {
+		status := m.jobs[id].Status
+		if excess > 0 && (status == JobStatusSucceeded || status == JobStatusFailed) {
+			delete(m.jobs, id)
+			excess--
+			continue
+		}
+		kept = append(kept, id)
+	}
+	m.order = kept
+}
+
+func copyJob(job *Job) Job {
+	copied := *job
+	copied.Suppliers = append([]hotel_service.SupplierProgress{}, job.Suppliers...)
+	return copied
+}
+
+func newJobID() (string, error) {
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err != nil {
+		return "", fmt.Errorf("failed to generate job id: %w", err)
+	}
+	return hex.EncodeToString(id), nil
+}
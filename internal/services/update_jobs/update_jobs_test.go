@@ -0,0 +1,132 @@
+package update_jobs
+
+import (
+	"ascenda-loyalty-assignment/internal/services/hotel_service"
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func waitForJob(t *testing.T, manager *Manager, id string) Job {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		job, exists := manager.Get(id)
+		if exists && (job.Status == JobStatusSucceeded || job.Status == JobStatusFailed) {
+			return job
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", id)
+	return Job{}
+}
+
+func TestManagerRunsJobs(t *testing.T) {
+	testCases := []struct {
+		description       string
+		options           hotel_service.UpdateOptions
+		runErr            error
+		expectedStatus    JobStatus
+		expectedError     string
+		expectedSuppliers []hotel_service.SupplierProgress
+	}{
+		{
+			description:    "successful update",
+			expectedStatus: JobStatusSucceeded,
+			expectedSuppliers: []hotel_service.SupplierProgress{
+				{Supplier: "acme", Status: hotel_service.SupplierStatusFetched, Hotels: 3},
+				{Supplier: "paperflies", Status: hotel_service.SupplierStatusFailed, Error: "timeout"},
+			},
+		},
+		{
+			description:    "dry run",
+			options:        hotel_service.UpdateOptions{DryRun: true},
+			expectedStatus: JobStatusSucceeded,
+			expectedSuppliers: []hotel_service.SupplierProgress{
+				{Supplier: "acme", Status: hotel_service.SupplierStatusFetched, Hotels: 3},
+				{Supplier: "paperflies", Status: hotel_service.SupplierStatusFailed, Error: "timeout"},
+			},
+		},
+		{
+			description:       "failed update",
+			runErr:            fmt.Errorf("unable to update new hotel data"),
+			expectedStatus:    JobStatusFailed,
+			expectedError:     "unable to update new hotel data",
+			expectedSuppliers: []hotel_service.SupplierProgress{},
+		},
+	}
+
+	logger := logging.LogrusLogger()
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			run := func(ctx context.Context, options hotel_service.UpdateOptions) (hotel_service.UpdateResult, error) {
+				if tc.runErr != nil {
+					return hotel_service.UpdateResult{}, tc.runErr
+				}
+				options.Progress(hotel_service.SupplierProgress{Supplier: "paperflies", Status: hotel_service.SupplierStatusFetching})
+				options.Progress(hotel_service.SupplierProgress{Supplier: "acme", Status: hotel_service.SupplierStatusFetching})
+				options.Progress(hotel_service.SupplierProgress{Supplier: "acme", Status: hotel_service.SupplierStatusFetched, Hotels: 3})
+				options.Progress(hotel_service.SupplierProgress{Supplier: "paperflies", Status: hotel_service.SupplierStatusFailed, Error: "timeout"})
+				return hotel_service.UpdateResult{Sources: []string{"acme"}, DryRun: options.DryRun}, nil
+			}
+			manager := NewManager(logger, run, 1, 10)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			manager.Start(ctx)
+
+			queued, err := manager.Enqueue(tc.options)
+			assert.NoError(t, err)
+			assert.NotEmpty(t, queued.ID)
+			assert.Equal(t, tc.options.DryRun, queued.DryRun)
+
+			job := waitForJob(t, manager, queued.ID)
+			assert.Equal(t, tc.expectedStatus, job.Status)
+			assert.Equal(t, tc.expectedError, job.Error)
+			assert.Equal(t, tc.expectedSuppliers, job.Suppliers)
+			assert.NotNil(t, job.StartedAt)
+			assert.NotNil(t, job.FinishedAt)
+			if tc.expectedStatus == JobStatusSucceeded {
+				assert.Equal(t, &hotel_service.UpdateResult{Sources: []string{"acme"}, DryRun: tc.options.DryRun}, job.Result)
+			} else {
+				assert.Nil(t, job.Result)
+			}
+		})
+	}
+}
+
+func TestManagerQueue(t *testing.T) {
+	logger := logging.LogrusLogger()
+	release := make(chan struct{})
+	run := func(ctx context.Context, options hotel_service.UpdateOptions) (hotel_service.UpdateResult, error) {
+		<-release
+		return hotel_service.UpdateResult{}, nil
+	}
+	manager := NewManager(logger, run, 1, 2)
+
+	first, err := manager.Enqueue(hotel_service.UpdateOptions{})
+	assert.NoError(t, err)
+	_, err = manager.Enqueue(hotel_service.UpdateOptions{})
+	assert.ErrorIs(t, err, ErrQueueFull)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	manager.Start(ctx)
+	close(release)
+	waitForJob(t, manager, first.ID)
+
+	second, err := manager.Enqueue(hotel_service.UpdateOptions{})
+	assert.NoError(t, err)
+	waitForJob(t, manager, second.ID)
+	third, err := manager.Enqueue(hotel_service.UpdateOptions{})
+	assert.NoError(t, err)
+	waitForJob(t, manager, third.ID)
+
+	_, exists := manager.Get(first.ID)
+	assert.False(t, exists, "the oldest finished job is evicted")
+	_, exists = manager.Get(third.ID)
+	assert.True(t, exists)
+	_, exists = manager.Get("unknown")
+	assert.False(t, exists)
+}
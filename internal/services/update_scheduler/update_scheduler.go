@@ -0,0 +1,183 @@
+package update_scheduler
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"ascenda-loyalty-assignment/utils"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/robfig/cron/v3"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// RunFunc runs a single supplier refresh.
+type RunFunc func(ctx context.Context) error
+
+// ScheduleConfig is the refresh schedule. Cron is a standard five field expression that takes precedence over
+// Interval, Jitter delays every run by a random duration up to its value so instances do not fetch together.
+type ScheduleConfig struct {
+	Enabled  bool     `json:"enabled"`
+	Interval Duration `json:"interval"`
+	Cron     string   `json:"cron"`
+	Jitter   Duration `json:"jitter"`
+}
+
+// Duration is a time.Duration read from a JSON string such as "30m".
+type Duration time.Duration
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("duration must be a string such as \"30m\": %w", err)
+	}
+	if value == "" {
+		*d = 0
+		return nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func LoadScheduleConfig(filePath string) (ScheduleConfig, error) {
+	data, err := utils.ReadJSONFile(filePath)
+	if err != nil {
+		return ScheduleConfig{}, err
+	}
+	var config ScheduleConfig
+	err = json.Unmarshal(data, &config)
+	if err != nil {
+		return ScheduleConfig{}, fmt.Errorf("invalid update schedule file %s: %w", filePath, err)
+	}
+	return config, nil
+}
+
+// Status reports the last run of the scheduler and when the next one is due.
+type Status struct {
+	Enabled      bool       `json:"enabled"`
+	Running      bool       `json:"running"`
+	LastRunAt    *time.Time `json:"last_run_at,omitempty"`
+	LastDuration string     `json:"last_duration,omitempty"`
+	LastOutcome  string     `json:"last_outcome,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
+	NextRunAt    *time.Time `json:"next_run_at,omitempty"`
+}
+
+// Scheduler runs a RunFunc on a schedule. Runs are single-flight: a run that is due while another one is in
+// progress is skipped.
+type Scheduler struct {
+	logger   logging.Logger
+	run      RunFunc
+	schedule cron.Schedule
+	jitter   time.Duration
+
+	mu      sync.Mutex
+	running bool
+	status  Status
+}
+
+func NewScheduler(logger logging.Logger, config ScheduleConfig, run RunFunc) (*Scheduler, error) {
+	if config.Jitter < 0 {
+		return nil, fmt.Errorf("jitter must not be negative")
+	}
+	var schedule cron.Schedule
+	if config.Cron != "" {
+		var err error
+		schedule, err = cron.ParseStandard(config.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", config.Cron, err)
+		}
+	} else {
+		if config.Interval <= 0 {
+			return nil, fmt.Errorf("either an interval or a cron expression is required")
+		}
+		schedule = cron.Every(time.Duration(config.Interval))
+	}
+	return &Scheduler{
+		logger:   logger,
+		run:      run,
+		schedule: schedule,
+		jitter:   time.Duration(config.Jitter),
+		status:   Status{Enabled: true},
+	}, nil
+}
+
+// Start runs the scheduler until ctx is done.
+func (s *Scheduler) Start(ctx context.Context) {
+	go func() {
+		for {
+			next := s.nextRun(time.Now())
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+				s.RunOnce(ctx)
+			}
+		}
+	}()
+}
+
+func (s *Scheduler) nextRun(now time.Time) time.Time {
+	next := s.schedule.Next(now).UTC()
+	if s.jitter > 0 {
+		next = next.Add(time.Duration(rand.Int63n(int64(s.jitter))))
+	}
+	s.mu.Lock()
+	s.status.NextRunAt = &next
+	s.mu.Unlock()
+	return next
+}
+
+// RunOnce runs the refresh now unless one is already running, it reports whether a run happened.
+func (s *Scheduler) RunOnce(ctx context.Context) bool {
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		s.logger.Warn("Skipping scheduled supplier refresh, the previous one is still running")
+		return false
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	startedAt := time.Now().UTC()
+	err := s.run(ctx)
+	duration := time.Since(startedAt)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+	s.status.LastRunAt = &startedAt
+	s.status.LastDuration = duration.String()
+	s.status.LastOutcome = OutcomeSuccess
+	s.status.LastError = ""
+	if err != nil {
+		s.logger.Error("Scheduled supplier refresh failed", err)
+		s.status.LastOutcome = OutcomeFailure
+		s.status.LastError = err.Error()
+	}
+	return true
+}
+
+func (s *Scheduler) Status() Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status := s.status
+	status.Running = s.running
+	return status
+}
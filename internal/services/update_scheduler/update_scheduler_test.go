@@ -0,0 +1,151 @@
+package update_scheduler
+
+import (
+	"ascenda-loyalty-assignment/pkg/logging"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestNewScheduler(t *testing.T) {
+	testCases := []struct {
+		description   string
+		config        ScheduleConfig
+		expectedError bool
+	}{
+		{
+			description: "interval",
+			config:      ScheduleConfig{Enabled: true, Interval: Duration(time.Hour)},
+		},
+		{
+			description: "cron expression",
+			config:      ScheduleConfig{Enabled: true, Cron: "0 */6 * * *"},
+		},
+		{
+			description:   "invalid cron expression",
+			config:        ScheduleConfig{Enabled: true, Cron: "every day"},
+			expectedError: true,
+		},
+		{
+			description:   "no interval nor cron expression",
+			config:        ScheduleConfig{Enabled: true},
+			expectedError: true,
+		},
+		{
+			description:   "negative jitter",
+			config:        ScheduleConfig{Enabled: true, Interval: Duration(time.Hour), Jitter: Duration(-time.Minute)},
+			expectedError: true,
+		},
+	}
+
+	logger := logging.LogrusLogger()
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			scheduler, err := NewScheduler(logger, tc.config, func(ctx context.Context) error { return nil })
+			if tc.expectedError {
+				assert.Error(t, err)
+				assert.Nil(t, scheduler)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, scheduler)
+		})
+	}
+}
+
+func TestScheduleConfigDurations(t *testing.T) {
+	var config ScheduleConfig
+	err := json.Unmarshal([]byte(`{"enabled": true, "interval": "30m", "jitter": ""}`), &config)
+	assert.NoError(t, err)
+	assert.Equal(t, ScheduleConfig{Enabled: true, Interval: Duration(30 * time.Minute)}, config)
+
+	err = json.Unmarshal([]byte(`{"interval": 30}`), &config)
+	assert.Error(t, err)
+}
+
+func TestSchedulerNextRun(t *testing.T) {
+	logger := logging.LogrusLogger()
+	now := time.Date(2024, 11, 1, 8, 0, 0, 0, time.UTC)
+
+	scheduler, err := NewScheduler(logger, ScheduleConfig{Cron: "30 9 * * *"}, func(ctx context.Context) error { return nil })
+	assert.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 11, 1, 9, 30, 0, 0, time.UTC), scheduler.nextRun(now))
+	assert.Equal(t, time.Date(2024, 11, 1, 9, 30, 0, 0, time.UTC), *scheduler.Status().NextRunAt)
+
+	scheduler, err = NewScheduler(logger, ScheduleConfig{Interval: Duration(time.Hour), Jitter: Duration(time.Minute)}, func(ctx context.Context) error { return nil })
+	assert.NoError(t, err)
+	for i := 0; i < 20; i++ {
+		next := scheduler.nextRun(now)
+		assert.False(t, next.Before(now.Add(time.Hour)))
+		assert.True(t, next.Before(now.Add(time.Hour+time.Minute)))
+	}
+}
+
+func TestSchedulerRunOnce(t *testing.T) {
+	testCases := []struct {
+		description     string
+		runErr          error
+		expectedOutcome string
+		expectedError   string
+	}{
+		{
+			description:     "successful run",
+			expectedOutcome: OutcomeSuccess,
+		},
+		{
+			description:     "failed run",
+			runErr:          fmt.Errorf("unable to update new hotel data"),
+			expectedOutcome: OutcomeFailure,
+			expectedError:   "unable to update new hotel data",
+		},
+	}
+
+	logger := logging.LogrusLogger()
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			scheduler, err := NewScheduler(logger, ScheduleConfig{Interval: Duration(time.Hour)}, func(ctx context.Context) error {
+				return tc.runErr
+			})
+			assert.NoError(t, err)
+
+			assert.True(t, scheduler.RunOnce(context.Background()))
+			status := scheduler.Status()
+			assert.True(t, status.Enabled)
+			assert.False(t, status.Running)
+			assert.NotNil(t, status.LastRunAt)
+			assert.NotEmpty(t, status.LastDuration)
+			assert.Equal(t, tc.expectedOutcome, status.LastOutcome)
+			assert.Equal(t, tc.expectedError, status.LastError)
+		})
+	}
+}
+
+func TestSchedulerSingleFlight(t *testing.T) {
+	logger := logging.LogrusLogger()
+	started := make(chan struct{})
+	release := make(chan struct{})
+	runs := 0
+	scheduler, err := NewScheduler(logger, ScheduleConfig{Interval: Duration(time.Hour)}, func(ctx context.Context) error {
+		runs++
+		close(started)
+		<-release
+		return nil
+	})
+	assert.NoError(t, err)
+
+	done := make(chan bool)
+	go func() {
+		done <- scheduler.RunOnce(context.Background())
+	}()
+	<-started
+	assert.True(t, scheduler.Status().Running)
+	assert.False(t, scheduler.RunOnce(context.Background()))
+
+	close(release)
+	assert.True(t, <-done)
+	assert.Equal(t, 1, runs)
+	assert.False(t, scheduler.Status().Running)
+}
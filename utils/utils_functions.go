@@ -2,9 +2,11 @@ package utils
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 	"strings"
 )
 
@@ -29,8 +31,49 @@ func WriteJSONFile(filePath string, data interface{}) error {
 	if err != nil {
 		return fmt.Errorf("unable to update new hotel data")
 	}
-	err = ioutil.WriteFile(filePath, jsonData, 0644)
-	return err
+	return WriteFileAtomic(filePath, jsonData, 0644)
+}
+
+// WriteFileAtomic writes data to a temporary file in the target directory, syncs it, then renames it over
+// the target, so readers and crashes only ever see the previous or the new content.
+func WriteFileAtomic(filePath string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(filePath)
+	tempFile, err := os.CreateTemp(dir, "."+filepath.Base(filePath)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tempPath := tempFile.Name()
+	defer os.Remove(tempPath)
+
+	if _, err = tempFile.Write(data); err == nil {
+		err = tempFile.Sync()
+	}
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if err = os.Chmod(tempPath, perm); err != nil {
+		return err
+	}
+	if err = os.Rename(tempPath, filePath); err != nil {
+		return err
+	}
+	return syncDir(dir)
+}
+
+// syncDir persists the directory entry of a renamed file.
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	if err = d.Sync(); err != nil && !errors.Is(err, os.ErrInvalid) {
+		return err
+	}
+	return nil
 }
 
 func SliceContains(slice []string, item string) bool {